@@ -1,12 +1,9 @@
 package assets
 
 import (
+	"bytes"
 	"embed"
-	"log"
-	"os"
 
-	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
@@ -19,13 +16,19 @@ var ScoreFace = mustLoadFace("Fonts/Kenney Mini.ttf", 32)
 var ScoreFaceBig = mustLoadFace("Fonts/Kenney Mini.ttf", 42)
 var ScoreFont = mustLoadFont("Fonts/Kenney Mini.ttf", 32)
 
-var (
-	audioContext *audio.Context
-	bgmPlayer    *audio.Player
-)
-
+// mustLoadFace load face from font file, GoTextFace can be drawn in any size, so text is sharp on HiDPI screens
 func mustLoadFace(name string, size float64) text.Face {
-	return text.NewGoXFace(mustLoadFont(name, size))
+	fontdata, err := assets.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	source, err := text.NewGoTextFaceSource(bytes.NewReader(fontdata))
+	if err != nil {
+		panic(err)
+	}
+
+	return &text.GoTextFace{Source: source, Size: size}
 }
 
 func mustLoadFont(name string, size float64) font.Face {
@@ -48,25 +51,3 @@ func mustLoadFont(name string, size float64) font.Face {
 
 	return opentypeFace
 }
-
-func CreatePlayer() *audio.Player {
-	// Load background music (OGG/Vorbis format recommended)
-	file, err := os.Open("assets/Music/menu.ogg")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Decode the audio file
-	decoded, err := vorbis.DecodeWithSampleRate(44100, file)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Create audio player
-	bgmPlayer, err = audioContext.NewPlayer(decoded)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	return bgmPlayer
-}
@@ -0,0 +1,249 @@
+package assets
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log"
+	"math"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+const (
+	sampleRate = 44100
+	// fadeTicks duration of music crossfade
+	fadeTicks = 60
+
+	// cutoff of play music filter for intensity 0 and 1
+	minCutoff = 600.0
+	maxCutoff = 20000.0
+	// minIntensityGain play music volume for intensity 0
+	minIntensityGain = 0.8
+)
+
+// music files
+const (
+	MusicMenu = "Music/menu.ogg"
+	MusicPlay = "Music/play.ogg"
+)
+
+type Sound int
+
+// sound effects
+const (
+	SoundJump Sound = iota
+	SoundSavePoint
+	SoundRedHit
+	SoundDeath
+)
+
+// Audio plays music and sound effects from embedded files
+type Audio struct {
+	context *audio.Context
+
+	music map[string]*audio.Player
+	// current is playing, previous is fading out
+	current  *audio.Player
+	previous *audio.Player
+	fade     int
+
+	// intensity filter of play music
+	intensity *intensityStream
+
+	// sounds decoded PCM of sound effects
+	sounds map[Sound][]byte
+
+	musicVolume float64
+	soundVolume float64
+}
+
+func NewAudio() (*Audio, error) {
+	a := &Audio{
+		context:     audio.NewContext(sampleRate),
+		music:       map[string]*audio.Player{},
+		sounds:      map[Sound][]byte{},
+		musicVolume: 1,
+		soundVolume: 1,
+		fade:        fadeTicks,
+	}
+
+	// music loops forever
+	for _, name := range []string{MusicMenu, MusicPlay} {
+		stream, err := decodeOgg(name)
+		if err != nil {
+			return nil, err
+		}
+
+		var src io.ReadSeeker = audio.NewInfiniteLoop(stream, stream.Length())
+		// play music reacts to the game
+		if name == MusicPlay {
+			a.intensity = &intensityStream{src: src}
+			src = a.intensity
+		}
+
+		player, err := a.context.NewPlayer(src)
+		if err != nil {
+			return nil, err
+		}
+		a.music[name] = player
+	}
+
+	// sound effects
+	jump, err := decodeOgg("Music/jump.ogg")
+	if err != nil {
+		return nil, err
+	}
+	a.sounds[SoundJump], err = io.ReadAll(jump)
+	if err != nil {
+		return nil, err
+	}
+
+	a.sounds[SoundSavePoint] = synthSound(600, 1200, 0.15, 0.4)
+	a.sounds[SoundRedHit] = synthSound(220, 110, 0.12, 0.5)
+	a.sounds[SoundDeath] = synthSound(400, 50, 0.6, 0.6)
+
+	return a, nil
+}
+
+// decodeOgg decode embedded ogg file
+func decodeOgg(name string) (*vorbis.Stream, error) {
+	data, err := assets.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return vorbis.DecodeWithSampleRate(sampleRate, bytes.NewReader(data))
+}
+
+// synthSound generate sine sweep from freqFrom to freqTo with fade out
+func synthSound(freqFrom, freqTo, seconds, volume float64) []byte {
+	samples := int(seconds * sampleRate)
+	// 16 bit stereo
+	pcm := make([]byte, samples*4)
+	phase := 0.0
+
+	for i := 0; i < samples; i++ {
+		t := float64(i) / float64(samples)
+		freq := freqFrom + (freqTo-freqFrom)*t
+		phase += 2 * math.Pi * freq / sampleRate
+
+		v := int16(math.Sin(phase) * (1 - t) * volume * math.MaxInt16)
+		binary.LittleEndian.PutUint16(pcm[i*4:], uint16(v))
+		binary.LittleEndian.PutUint16(pcm[i*4+2:], uint16(v))
+	}
+
+	return pcm
+}
+
+// PlayMusic crossfade to music, does nothing if music is already playing
+func (a *Audio) PlayMusic(name string) {
+	player := a.music[name]
+	if player == nil || player == a.current {
+		return
+	}
+
+	if a.previous != nil && a.previous != player {
+		a.previous.Pause()
+	}
+
+	a.previous = a.current
+	a.current = player
+	a.fade = 0
+
+	a.current.SetVolume(0)
+	a.current.Play()
+}
+
+// Update advance crossfade, call every tick
+func (a *Audio) Update() {
+	if a.fade >= fadeTicks {
+		return
+	}
+	a.fade++
+
+	t := float64(a.fade) / fadeTicks
+	a.current.SetVolume(a.musicVolume * t)
+
+	if a.previous != nil {
+		a.previous.SetVolume(a.musicVolume * (1 - t))
+
+		if a.fade == fadeTicks {
+			a.previous.Pause()
+			if err := a.previous.Rewind(); err != nil {
+				log.Println(err)
+			}
+			a.previous = nil
+		}
+	}
+}
+
+// PlaySound play sound effect
+func (a *Audio) PlaySound(sound Sound) {
+	if a.soundVolume == 0 {
+		return
+	}
+
+	player := a.context.NewPlayerFromBytes(a.sounds[sound])
+	player.SetVolume(a.soundVolume)
+	player.Play()
+}
+
+func (a *Audio) SetMusicVolume(volume float64) {
+	a.musicVolume = volume
+	if a.current != nil && a.fade >= fadeTicks {
+		a.current.SetVolume(volume)
+	}
+}
+
+func (a *Audio) SetSoundVolume(volume float64) {
+	a.soundVolume = volume
+}
+
+// intensityStream low pass filter over music, the cutoff grows with intensity
+type intensityStream struct {
+	src io.ReadSeeker
+	// intensity float64 bits, set from game and read from audio goroutine
+	intensity atomic.Uint64
+	// filter state of left and right channels
+	left, right float64
+}
+
+func (s *intensityStream) setIntensity(intensity float64) {
+	s.intensity.Store(math.Float64bits(math.Max(0, math.Min(1, intensity))))
+}
+
+func (s *intensityStream) Read(p []byte) (int, error) {
+	n, err := s.src.Read(p)
+
+	intensity := math.Float64frombits(s.intensity.Load())
+	cutoff := minCutoff * math.Pow(maxCutoff/minCutoff, intensity)
+	alpha := 1 - math.Exp(-2*math.Pi*cutoff/sampleRate)
+	gain := minIntensityGain + (1-minIntensityGain)*intensity
+
+	// 16 bit stereo frames, incomplete frame is left as is
+	for i := 0; i+4 <= n; i += 4 {
+		l := float64(int16(binary.LittleEndian.Uint16(p[i:])))
+		r := float64(int16(binary.LittleEndian.Uint16(p[i+2:])))
+
+		s.left += alpha * (l - s.left)
+		s.right += alpha * (r - s.right)
+
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(s.left*gain)))
+		binary.LittleEndian.PutUint16(p[i+2:], uint16(int16(s.right*gain)))
+	}
+
+	return n, err
+}
+
+func (s *intensityStream) Seek(offset int64, whence int) (int64, error) {
+	s.left, s.right = 0, 0
+	return s.src.Seek(offset, whence)
+}
+
+// SetIntensity set play music intensity from 0 (calm, muffled) to 1 (tense)
+func (a *Audio) SetIntensity(intensity float64) {
+	a.intensity.setIntensity(intensity)
+}
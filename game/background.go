@@ -0,0 +1,128 @@
+package game
+
+import (
+	"ball/assets"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// background layer types
+const (
+	layerGrid    = "grid"
+	layerSkyline = "skyline"
+	layerTape    = "tape"
+)
+
+var (
+	gridColor    = color.RGBA{15, 35, 35, 255}
+	skylineColor = color.RGBA{12, 22, 28, 255}
+	tapeColor    = color.RGBA{60, 80, 80, 255}
+)
+
+// BackgroundLayer parallax layer behind the ground
+type BackgroundLayer struct {
+	Type string `json:"type"`
+	// Factor part of camera movement, 0 - layer doesn't move, 1 - moves with ground
+	Factor float64 `json:"factor"`
+	// Spacing between grid lines or buildings, 0 - default
+	Spacing float64 `json:"spacing,omitempty"`
+}
+
+// defaultBackground is used when level has no background
+var defaultBackground = []BackgroundLayer{
+	{Type: layerGrid, Factor: 0.2, Spacing: 100},
+	{Type: layerSkyline, Factor: 0.4, Spacing: 90},
+	{Type: layerTape, Factor: 0.7},
+}
+
+// getBackground return background layers of level or default
+func (l *Level) getBackground() []BackgroundLayer {
+	if len(l.Background) == 0 {
+		return defaultBackground
+	}
+	return l.Background
+}
+
+// drawBackground draw parallax layers
+func (g *Game) drawBackground(screen *ebiten.Image) {
+	for _, layer := range g.getCurrentLevel().getBackground() {
+		offsetX := g.camera.X * layer.Factor
+		offsetY := g.camera.Y * layer.Factor
+
+		switch layer.Type {
+		case layerGrid:
+			drawGridLayer(screen, offsetX, offsetY, layer.spacing(100))
+		case layerSkyline:
+			drawSkylineLayer(screen, offsetX, layer.spacing(90))
+		case layerTape:
+			g.drawTapeLayer(screen, offsetX)
+		}
+	}
+}
+
+func (l BackgroundLayer) spacing(def float64) float64 {
+	if l.Spacing <= 0 {
+		return def
+	}
+	return l.Spacing
+}
+
+// posMod modulo which is never negative
+func posMod(a, b float64) float64 {
+	return math.Mod(math.Mod(a, b)+b, b)
+}
+
+// drawGridLayer distant grid of price lines
+func drawGridLayer(screen *ebiten.Image, offsetX, offsetY, spacing float64) {
+	width, height := float64(screen.Bounds().Dx())/uiScale, float64(screen.Bounds().Dy())/uiScale
+
+	for x := -posMod(offsetX, spacing); x < width; x += spacing {
+		uiStrokeLine(screen, float32(x), 0, float32(x), float32(height), 1, gridColor)
+	}
+	for y := -posMod(offsetY, spacing); y < height; y += spacing {
+		uiStrokeLine(screen, 0, float32(y), float32(width), float32(y), 1, gridColor)
+	}
+}
+
+// drawSkylineLayer silhouettes of buildings at the bottom of screen
+func drawSkylineLayer(screen *ebiten.Image, offsetX, spacing float64) {
+	width, screenHeight := float64(screen.Bounds().Dx())/uiScale, float64(screen.Bounds().Dy())/uiScale
+
+	first := int(math.Floor(offsetX / spacing))
+	for i := first; float64(i)*spacing-offsetX < width; i++ {
+		// the same building always has the same height
+		h := uint32(i) * 2654435761
+		height := 100 + float64(h%250)
+		buildingWidth := spacing * (0.6 + float64(h%30)/100)
+
+		uiFillRect(screen,
+			float32(float64(i)*spacing-offsetX),
+			float32(screenHeight-height),
+			float32(buildingWidth),
+			float32(height),
+			skylineColor)
+	}
+}
+
+// drawTapeLayer ticker tape with the price under the ball
+func (g *Game) drawTapeLayer(screen *ebiten.Image, offsetX float64) {
+	level := g.getCurrentLevel()
+	price := 0.0
+	if g.ball != nil {
+		price = g.ball.pos.Y / multiplyChartY
+	}
+
+	tape := fmt.Sprintf("%s %.2f   ", level.Ticker, price)
+	w, _ := text.Measure(tape, assets.ScoreFace, 0)
+
+	for x := -posMod(offsetX, w); x < g.screenWidth; x += w {
+		options := &text.DrawOptions{}
+		options.GeoM.Translate(x, 60)
+		options.ColorScale.ScaleWithColor(tapeColor)
+		uiDrawText(screen, tape, assets.ScoreFace, options)
+	}
+}
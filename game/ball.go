@@ -1,14 +1,23 @@
 package game
 
 import (
+	"ball/assets"
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
+const (
+	// maxVelX, maxVelY limits of ball velocity per tick
+	maxVelX = 7.0
+	maxVelY = 20.0
+)
+
 type Ball struct {
-	pos         Vector
+	pos Vector
+	// prevPos position before the last move, used to find collisions on the way
+	prevPos     Vector
 	vel         Vector
 	radius      float64
 	onGround    bool
@@ -21,25 +30,36 @@ type Ball struct {
 	isDied bool
 
 	doubleJump int
+
+	// onRed ball touches red segment
+	onRed bool
+
+	anim slimeAnim
+
+	// airTicks ticks since the ball touched ground
+	airTicks int
+	// jumpBuffer ticks left while pressed jump waits for ground
+	jumpBuffer int
+	// jumpHeld Space is held since jump, releasing it cuts the jump
+	jumpHeld bool
+
+	// form current form of the slime
+	form *Form
+	// groundNormal average normal of touched segments, zero in the air
+	groundNormal Vector
 }
 
 func NewBall(spawnPos Vector) *Ball {
 	ball := &Ball{
 		pos:          Vector{spawnPos.X, spawnPos.Y},
+		prevPos:      Vector{spawnPos.X, spawnPos.Y},
 		vel:          Vector{0, 0},
 		radius:       ballPhysicA.radius,
 		currPhyState: &ballPhysicA,
 		doubleJump:   0,
+		form:         slimeForm,
 	}
-
-	return ball
-}
-
-func NewEnemyBall() *Ball {
-	ball := &Ball{
-		radius: ballPhysicA.radius,
-		pos:    Vector{-100, 0},
-	}
+	ball.anim.radius = ball.radius
 
 	return ball
 }
@@ -47,7 +67,7 @@ func NewEnemyBall() *Ball {
 func (b *Ball) Update(ground []*Segment, game *Game) {
 
 	// change state
-	b.currPhyState = &ballPhysicA
+	b.currPhyState = &b.form.physic
 	b.radius = b.currPhyState.radius
 
 	// process user clicks
@@ -60,18 +80,18 @@ func (b *Ball) Update(ground []*Segment, game *Game) {
 	b.vel.Y += b.currPhyState.gravity
 
 	// limit velocity
-	if b.vel.Y > 20 {
-		b.vel.Y = 20
+	if b.vel.Y > maxVelY {
+		b.vel.Y = maxVelY
 	}
 
-	if b.vel.Y < -20 {
-		b.vel.Y = -20
+	if b.vel.Y < -maxVelY {
+		b.vel.Y = -maxVelY
 	}
-	if b.vel.X > 7 {
-		b.vel.X = 7
+	if b.vel.X > maxVelX {
+		b.vel.X = maxVelX
 	}
-	if b.vel.X < -7 {
-		b.vel.X = -7
+	if b.vel.X < -maxVelX {
+		b.vel.X = -maxVelX
 	}
 
 	// limit edge X
@@ -81,17 +101,19 @@ func (b *Ball) Update(ground []*Segment, game *Game) {
 	}
 
 	// Apply velocity
+	b.prevPos = b.pos
 	b.pos = b.pos.Add(b.vel)
 
 	// Dampen velocity
 	b.vel.X *= 0.995
 	b.vel.Y *= 0.995
 
+	b.anim.update(b)
 }
 
 // updateControls process user clicks
 func (b *Ball) updateControls(game *Game) {
-	if ebiten.IsKeyPressed(ebiten.KeyShift) {
+	if b.form.canInflate && ebiten.IsKeyPressed(ebiten.KeyShift) {
 		b.currPhyState = &ballPhysicB
 	} else if b.currPhyState.state == phyStateB {
 		b.pos.Y += math.Abs(ballPhysicB.radius - ballPhysicA.radius)
@@ -100,7 +122,7 @@ func (b *Ball) updateControls(game *Game) {
 	// Move left/right
 	if ebiten.IsKeyPressed(ebiten.KeyRight) {
 		b.vel.X += b.currPhyState.speedRun
-		b.facingRight = false
+		b.facingRight = true
 
 		if b.vel.X < 0 {
 			b.vel.X = 0
@@ -108,48 +130,98 @@ func (b *Ball) updateControls(game *Game) {
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
 		b.vel.X -= b.currPhyState.speedRun
-		b.facingRight = true
+		b.facingRight = false
 
 		if b.vel.X > 0 {
 			b.vel.X = 0
 		}
 	}
 
-	// Jump if on ground
-	if b.currPhyState == &ballPhysicA {
-		if inpututil.IsKeyJustPressed(ebiten.KeySpace) && game.getCurrentLevel().Score.getScore() > 0 {
+	b.updateJump(game)
 
-			if b.doubleJump < 1 && !b.onGround {
-				b.doubleJump++
-				b.vel = b.vel.Add(b.jumpVel)
-			}
+	if ebiten.IsKeyPressed(ebiten.KeyDown) && !b.onGround {
+		b.vel = b.vel.Sub(b.jumpVel)
+	}
 
-			if b.onGround {
-				b.onGround = false
-				game.getCurrentLevel().Score.minusScore(1)
-				b.vel = b.vel.Add(b.jumpVel)
-				for _, seg := range game.collisionSeg {
-					game.fractions = append(game.fractions, seg.closestPoint)
-				}
-			}
-		}
+	// controls of the form
+	if b.form.update != nil {
+		b.form.update(b, game)
+	}
+}
+
+// updateJump jump from ground, shortly after leaving it or shortly before landing,
+// every jump from ground costs 1 point of score
+func (b *Ball) updateJump(game *Game) {
+	p := b.currPhyState
+
+	if b.groundNormal != (Vector{}) {
+		b.airTicks = 0
+	} else {
+		b.airTicks++
+	}
+
+	// remember pressed jump for a few ticks, state "B" jumps while Space is held
+	justPressed := inpututil.IsKeyJustPressed(ebiten.KeySpace)
+	if justPressed || (p.state == phyStateB && ebiten.IsKeyPressed(ebiten.KeySpace)) {
+		b.jumpBuffer = p.jumpBufferTicks + 1
 	}
 
-	if b.currPhyState == &ballPhysicB {
-		if ebiten.IsKeyPressed(ebiten.KeySpace) && game.getCurrentLevel().Score.getScore() > 0 {
+	// variable jump height, releasing Space cuts the jump
+	if b.jumpHeld && (!ebiten.IsKeyPressed(ebiten.KeySpace) || b.vel.Y >= 0) {
+		if b.vel.Y < 0 {
+			b.vel.Y *= p.jumpCut
+		}
+		b.jumpHeld = false
+	}
 
-			if b.onGround {
-				b.onGround = false
-				game.getCurrentLevel().Score.minusScore(1)
-				b.vel = b.vel.Add(b.jumpVel)
-				for _, seg := range game.collisionSeg {
-					game.fractions = append(game.fractions, seg.closestPoint)
-				}
-			}
+	hasScore := game.getCurrentLevel().Score.getScore() > 0
+	switch {
+	// on ground or coyote time
+	case b.jumpBuffer > 0 && b.onGround && b.airTicks <= p.coyoteTicks && hasScore:
+		game.getCurrentLevel().Score.minusScore(1)
+		b.jump(game)
+		for _, seg := range game.collisionSeg {
+			game.particles.spray(seg.closestPoint, seg.normal, math.Pi/2, 4, 3, 30, ballColor)
 		}
+	// double jump, not if ground is so near that the buffered press jumps from it
+	case justPressed && p.state == phyStateA && b.doubleJump < 1 && hasScore && !b.groundAhead(game, p.jumpBufferTicks):
+		b.doubleJump++
+		b.jump(game)
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyDown) && !b.onGround {
-		b.vel = b.vel.Sub(b.jumpVel)
+	if b.jumpBuffer > 0 {
+		b.jumpBuffer--
 	}
 }
+
+// groundAhead ball falls on ground within ticks
+func (b *Ball) groundAhead(game *Game, ticks int) bool {
+	t := float64(ticks)
+	motion := b.vel.Mul(t).Add(Vector{0, b.currPhyState.gravity * t * t / 2})
+	if ticks <= 0 || motion.Y <= 0 {
+		return false
+	}
+
+	minX := math.Min(b.pos.X, b.pos.X+motion.X) - b.radius
+	maxX := math.Max(b.pos.X, b.pos.X+motion.X) + b.radius
+	game.nearSegments = game.grid.query(minX, maxX, game.nearSegments[:0])
+	for _, seg := range game.nearSegments {
+		if seg.IsMovingWall || seg.IsRisingFloor || seg.isBorder {
+			continue
+		}
+		if _, ok := sweepCircleSegment(b.pos, motion, b.radius, seg.A, seg.B); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// jump add jump velocity
+func (b *Ball) jump(game *Game) {
+	b.onGround = false
+	b.jumpBuffer = 0
+	b.jumpHeld = true
+	b.vel = b.vel.Add(b.jumpVel)
+	b.anim.jump()
+	game.audio.PlaySound(assets.SoundJump)
+}
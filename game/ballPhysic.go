@@ -7,6 +7,24 @@ const (
 	phyStateB
 )
 
+// surfaces of segments
+const (
+	surfaceGround = "ground"
+	surfaceRed    = "red"
+	surfaceBorder = "border"
+)
+
+// bounceMinImpact slower hits don't bounce, so the ball can rest on the ground
+const bounceMinImpact = 3.0
+
+// Material how the ball reacts to a surface
+type Material struct {
+	// Bounce restitution, 0 = no bounce, 1 = perfect bounce
+	Bounce float64 `json:"bounce"`
+	// Friction part of velocity along the surface which is kept
+	Friction float64 `json:"friction"`
+}
+
 type BallPhysic struct {
 	state        phyStateInt
 	gravity      float64
@@ -17,79 +35,76 @@ type BallPhysic struct {
 	jumpForce    float64
 	scrambleWall Vector
 	bounceFactor float64
+	// coyoteTicks ticks after leaving ground when jump is still possible
+	coyoteTicks int
+	// jumpBufferTicks ticks before landing when pressed jump is remembered
+	jumpBufferTicks int
+	// jumpCut part of upward velocity which is kept when Space is released, 1 = fixed jump height
+	jumpCut float64
+	// materials per surface, bounceFactor and friction are used for other surfaces
+	materials map[string]Material
 }
 
-// var ballPhysicA = BallPhysic{
-// 	state:        0,
-// 	gravity:      0.98,
-// 	friction:     0.98,
-// 	radius:       20.0,
-// 	speedRun:     1.0,
-// 	jump:         Vector{0.0, -1.0},
-// 	jumpForce:    13.0,
-// 	scrambleWall: Vector{0.0, -1.0},
-// }
-
-// var ballPhysicA = BallPhysic{
-// 	state:        0,
-// 	gravity:      0.8,
-// 	friction:     0.98,
-// 	radius:       25.0,
-// 	speedRun:     1.0,
-// 	jump:         Vector{0.0, -0.8},
-// 	jumpForce:    10.0,
-// 	scrambleWall: Vector{0.0, -0.0},
-// 	bounceFactor: 0.6, // 0 = no bounce, 1 = perfect bounce
-// }
-
-// noraml
-// var ballPhysicA = BallPhysic{
-// 	state:        0,
-// 	gravity:      1,
-// 	friction:     0.98,
-// 	radius:       30.0,
-// 	speedRun:     1.0,
-// 	jump:         Vector{0.0, -0.8},
-// 	jumpForce:    10.0,
-// 	scrambleWall: Vector{0.0, -0.0},
-// 	bounceFactor: 1, // 0 = no bounce, 1 = perfect bounce
-// }
+// material material of the surface
+func (p *BallPhysic) material(surface string) Material {
+	if m, ok := p.materials[surface]; ok {
+		return m
+	}
+	return Material{Bounce: p.bounceFactor, Friction: p.friction}
+}
 
-// test
-var ballPhysicA = BallPhysic{
-	state:        0,
-	gravity:      0.95,
-	friction:     0.9,
-	radius:       30.0,
-	speedRun:     2.0,
-	jump:         Vector{0.0, -3},
-	jumpForce:    10.0,
-	scrambleWall: Vector{0.0, 0.0},
-	bounceFactor: 0.0, // 0 = no bounce, 1 = perfect bounce
+// contactMaterial material of touched segments, the bounciest one wins
+func (p *BallPhysic) contactMaterial(segments []Segment) Material {
+	material := p.material(surfaceGround)
+	for i, seg := range segments {
+		m := p.material(seg.surface())
+		if i == 0 || m.Bounce > material.Bounce {
+			material = m
+		}
+	}
+	return material
 }
 
-//  normal
-// var ballPhysicB = BallPhysic{
-// 	state:        1,
-// 	gravity:      0.5,
-// 	friction:     0.1,
-// 	radius:       45,
-// 	speedRun:     2,
-// 	jump:         Vector{0.0, 0.0},
-// 	jumpForce:    10,
-// 	scrambleWall: Vector{0.0, -0.5},
-// 	bounceFactor: 0.0, // 0 = no bounce, 1 = perfect bounce
-// }
+// defaultBallPhysicA, defaultBallPhysicB initial physics, values missing in physics file are taken from them
+var defaultBallPhysicA = BallPhysic{
+	state:           phyStateA,
+	gravity:         0.95,
+	friction:        0.9,
+	radius:          30.0,
+	speedRun:        2.0,
+	jump:            Vector{0.0, -3},
+	jumpForce:       10.0,
+	scrambleWall:    Vector{0.0, 0.0},
+	bounceFactor:    0.0, // 0 = no bounce, 1 = perfect bounce
+	coyoteTicks:     6,
+	jumpBufferTicks: 6,
+	jumpCut:         0.5,
+	materials: map[string]Material{
+		surfaceGround: {Bounce: 0, Friction: 0.9},
+		surfaceRed:    {Bounce: 0.8, Friction: 0.95},
+	},
+}
 
-// test
-var ballPhysicB = BallPhysic{
-	state:        1,
-	gravity:      1,
-	friction:     0,
-	radius:       45,
-	speedRun:     2,
-	jump:         Vector{0.0, -0.7},
-	jumpForce:    20,
-	scrambleWall: Vector{0.0, -3},
-	bounceFactor: 0.0, // 0 = no bounce, 1 = perfect bounce
+var defaultBallPhysicB = BallPhysic{
+	state:           phyStateB,
+	gravity:         1,
+	friction:        0,
+	radius:          45,
+	speedRun:        2,
+	jump:            Vector{0.0, -0.7},
+	jumpForce:       20,
+	scrambleWall:    Vector{0.0, -3},
+	bounceFactor:    0.0, // 0 = no bounce, 1 = perfect bounce
+	coyoteTicks:     6,
+	jumpBufferTicks: 0,
+	jumpCut:         1,
+	materials: map[string]Material{
+		surfaceRed: {Bounce: 0.6, Friction: 0},
+	},
 }
+
+// ballPhysicA, ballPhysicB current physics, values are set from physics preset
+var (
+	ballPhysicA = defaultBallPhysicA
+	ballPhysicB = defaultBallPhysicB
+)
@@ -0,0 +1,58 @@
+package game
+
+import "testing"
+
+// TestBallGroundAhead double jump is skipped only if the ball lands within the jump buffer
+func TestBallGroundAhead(t *testing.T) {
+	radius := ballPhysicA.radius
+	ticks := ballPhysicA.jumpBufferTicks
+
+	tests := []struct {
+		name string
+		seg  *Segment
+		pos  Vector
+		vel  Vector
+		want bool
+	}{
+		{
+			name: "lands within buffer",
+			seg:  &Segment{A: Vector{-200, 0}, B: Vector{200, 0}},
+			pos:  Vector{0, -radius - 10},
+			vel:  Vector{0, 5},
+			want: true,
+		},
+		{
+			name: "ground too far",
+			seg:  &Segment{A: Vector{-200, 0}, B: Vector{200, 0}},
+			pos:  Vector{0, -radius - 1000},
+			vel:  Vector{0, 5},
+			want: false,
+		},
+		{
+			name: "rises from ground",
+			seg:  &Segment{A: Vector{-200, 0}, B: Vector{200, 0}},
+			pos:  Vector{0, -radius - 10},
+			vel:  Vector{0, -maxVelY},
+			want: false,
+		},
+		{
+			name: "moving wall is not ground",
+			seg:  &Segment{A: Vector{-200, 0}, B: Vector{200, 0}, IsMovingWall: true},
+			pos:  Vector{0, -radius - 10},
+			vel:  Vector{0, 5},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := &Game{ball: NewBall(tt.pos)}
+			game.grid.build([]*Segment{tt.seg})
+			game.ball.vel = tt.vel
+
+			if got := game.ball.groundAhead(game, ticks); got != tt.want {
+				t.Errorf("groundAhead = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 type Button struct {
@@ -21,9 +20,9 @@ type Button struct {
 
 func (b *Button) IsClicked() bool {
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		mx, my := ebiten.CursorPosition()
-		return float64(mx) > b.X && float64(mx) < b.X+b.Width &&
-			float64(my) > b.Y && float64(my) < b.Y+b.Height
+		mx, my := uiCursorPosition()
+		return mx > b.X && mx < b.X+b.Width &&
+			my > b.Y && my < b.Y+b.Height
 	}
 	return false
 }
@@ -35,9 +34,9 @@ func drawButtonText(screen *ebiten.Image, btn *Button) {
 
 func drawButton(screen *ebiten.Image, btn *Button) {
 	// Check hover state
-	mx, my := ebiten.CursorPosition()
-	hover := float64(mx) > btn.X && float64(mx) < btn.X+btn.Width &&
-		float64(my) > btn.Y && float64(my) < btn.Y+btn.Height
+	mx, my := uiCursorPosition()
+	hover := mx > btn.X && mx < btn.X+btn.Width &&
+		my > btn.Y && my < btn.Y+btn.Height
 
 	// Choose color
 	btnColor := btn.Color
@@ -46,12 +45,12 @@ func drawButton(screen *ebiten.Image, btn *Button) {
 	}
 
 	// Draw button
-	vector.DrawFilledRect(screen,
+	uiFillRect(screen,
 		float32(btn.X),
 		float32(btn.Y),
 		float32(btn.Width),
 		float32(btn.Height),
-		btnColor, false)
+		btnColor)
 }
 
 // drawText draw button text
@@ -60,19 +59,19 @@ func drawText(screen *ebiten.Image, btn *Button) {
 	options := &text.DrawOptions{}
 	options.GeoM.Translate(btn.X+(btn.Width-w)/2, btn.Y+(btn.Height-h)/2)
 	options.ColorScale.ScaleWithColor(color.White)
-	text.Draw(screen, btn.Text, assets.ScoreFace, options)
+	uiDrawText(screen, btn.Text, assets.ScoreFace, options)
 }
 
 // drawProgressButton draw button with level progress
 func drawProgressButton(screen *ebiten.Image, btn *Button, level *Level) {
 	drawButton(screen, btn)
 	progressWidth := btn.Width * float64(calculateLevelProgress(*level)) / 100
-	vector.DrawFilledRect(screen,
+	uiFillRect(screen,
 		float32(btn.X),
 		float32(btn.Y),
 		float32(progressWidth),
 		float32(btn.Height),
-		ballColor, false)
+		ballColor)
 
 	drawText(screen, btn)
 }
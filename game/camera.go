@@ -1,24 +1,161 @@
 package game
 
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// cameraLerp how fast camera follows target
+	cameraLerp = 0.1
+	// lookAheadFactor look ahead distance per unit of ball velocity
+	lookAheadFactor = 30.0
+	lookAheadLerp   = 0.03
+	// deadZoneY camera doesn't move vertically while ball is within this distance from center
+	deadZoneY = 120.0
+
+	zoomLerp = 0.03
+	// zoomFast zoom at high speed, zoomBig zoom when ball is inflated
+	zoomFast   = 0.8
+	zoomBig    = 0.85
+	zoomManual = 0.6
+	// fastSpeed ball speed when camera zooms out
+	fastSpeed = 12.0
+
+	// maxShake max shake offset in pixels
+	maxShake = 25.0
+	// shakeDecay trauma lost per tick
+	shakeDecay = 0.03
+	// hardLandingImpact min velocity into ground which shakes camera
+	hardLandingImpact = 12.0
+)
+
 // Camera struct
+// X, Y - top left corner of the view in level coordinates
 type Camera struct {
 	X, Y          float64
 	Width, Height float64
+	Zoom          float64
+
+	// lookAhead horizontal offset of the target in direction of velocity
+	lookAhead float64
+	// manualZoom zoom out by the player
+	manualZoom bool
+
+	// trauma 0..1, shake grows as square of trauma
+	trauma         float64
+	shakeX, shakeY float64
+}
+
+func newCamera() *Camera {
+	return &Camera{
+		Width:  float64(ScreenWidth),
+		Height: float64(ScreenHeight),
+		Zoom:   1,
+	}
 }
 
-func (c *Camera) Update(playerX, playerY float64) {
-	targetX := playerX - c.Width/2
-	targetY := playerY - c.Height/2
+// viewWidth width of the view in level coordinates
+func (c *Camera) viewWidth() float64 {
+	return c.Width / c.Zoom
+}
+
+// viewHeight height of the view in level coordinates
+func (c *Camera) viewHeight() float64 {
+	return c.Height / c.Zoom
+}
+
+func (c *Camera) Update(pos, vel Vector, inflated bool) {
+	// zoom out at high speed, when inflated or by player
+	targetZoom := 1.0
+	if vel.Len() > fastSpeed {
+		targetZoom = math.Min(targetZoom, zoomFast)
+	}
+	if inflated {
+		targetZoom = math.Min(targetZoom, zoomBig)
+	}
+	if c.manualZoom {
+		targetZoom = math.Min(targetZoom, zoomManual)
+	}
+
+	// keep center while zooming
+	centerX := c.X + c.viewWidth()/2
+	centerY := c.Y + c.viewHeight()/2
+	c.Zoom += (targetZoom - c.Zoom) * zoomLerp
+
+	// look ahead in direction of movement
+	c.lookAhead += (vel.X*lookAheadFactor - c.lookAhead) * lookAheadLerp
+	targetX := pos.X + c.lookAhead
+
+	// move vertically only out of dead zone
+	targetY := centerY
+	if pos.Y < centerY-deadZoneY {
+		targetY = pos.Y + deadZoneY
+	} else if pos.Y > centerY+deadZoneY {
+		targetY = pos.Y - deadZoneY
+	}
 
 	// Smooth camera movement
-	c.X += (targetX - c.X) * 0.1
-	c.Y += (targetY - c.Y) * 0.1
+	centerX += (targetX - centerX) * cameraLerp
+	centerY += (targetY - centerY) * cameraLerp
+
+	c.X = centerX - c.viewWidth()/2
+	c.Y = centerY - c.viewHeight()/2
 
 	// Keep camera within reasonable bounds
 	if c.X < 0 {
 		c.X = 0
 	}
-	// if c.Y < 0 {
-	// 	c.Y = 0
-	// }
+
+	c.updateShake()
+}
+
+// toggleZoom zoom out or back by the player
+func (c *Camera) toggleZoom() {
+	c.manualZoom = !c.manualZoom
+}
+
+// shake add trauma, 1 is max
+func (c *Camera) shake(trauma float64) {
+	c.trauma = math.Min(1, c.trauma+trauma)
+}
+
+func (c *Camera) updateShake() {
+	c.trauma = math.Max(0, c.trauma-shakeDecay)
+	power := c.trauma * c.trauma * maxShake
+	c.shakeX = (rand.Float64()*2 - 1) * power
+	c.shakeY = (rand.Float64()*2 - 1) * power
+}
+
+// toScreen convert level position to pixels of screen image
+func (c *Camera) toScreen(p Vector) (float32, float32) {
+	return float32(((p.X-c.X)*c.Zoom + c.shakeX) * uiScale), float32(((p.Y-c.Y)*c.Zoom + c.shakeY) * uiScale)
+}
+
+// toUI convert level position to screen units
+func (c *Camera) toUI(p Vector) (float64, float64) {
+	x, y := c.toScreen(p)
+	return float64(x) / uiScale, float64(y) / uiScale
+}
+
+// toScreenLen convert level length to pixels of screen image
+func (c *Camera) toScreenLen(l float64) float32 {
+	return float32(l * c.Zoom * uiScale)
+}
+
+// strokeLine draw line between level positions
+func (c *Camera) strokeLine(screen *ebiten.Image, a, b Vector, width float32, clr color.Color) {
+	ax, ay := c.toScreen(a)
+	bx, by := c.toScreen(b)
+	vector.StrokeLine(screen, ax, ay, bx, by, c.toScreenLen(float64(width)), clr, false)
+}
+
+// drawCircle draw filled circle in level position
+func (c *Camera) drawCircle(screen *ebiten.Image, pos Vector, radius float64, clr color.Color) {
+	x, y := c.toScreen(pos)
+	vector.DrawFilledCircle(screen, x, y, c.toScreenLen(radius), clr, false)
 }
@@ -0,0 +1,458 @@
+package game
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// enemy kinds
+const (
+	// enemyChaser rolls on ground and chases the player, it respawns ahead when left behind
+	enemyChaser = "chaser"
+	// enemyBouncer hops between peaks of the chart
+	enemyBouncer = "bouncer"
+	// enemyShortSeller hovers above the player and drops down
+	enemyShortSeller = "shortSeller"
+	// enemyTurret stands on ground and fires projectiles
+	enemyTurret = "turret"
+)
+
+const (
+	enemyGravity = 0.8
+	// enemyFriction part of velocity kept while rolling on ground
+	enemyFriction = 0.98
+	// enemyAccel, enemyMaxSpeed acceleration and speed at aggression 1
+	enemyAccel    = 0.35
+	enemyMaxSpeed = 8.0
+	// enemyAirControl part of acceleration in the air
+	enemyAirControl = 0.3
+	enemyMaxFall    = 15.0
+
+	enemyJumpSpeed = 14.0
+	// enemyJumpCooldown ticks between jumps
+	enemyJumpCooldown = 45
+	// enemyJumpRange player closer than this by X and higher than enemyJumpHeight makes enemy jump
+	enemyJumpRange  = 350.0
+	enemyJumpHeight = 80.0
+	// enemyStuckSpeed enemy slower than this while chasing is blocked and jumps
+	enemyStuckSpeed = 0.5
+	// enemyJumpChance chance to jump per tick at aggression 1
+	enemyJumpChance = 0.1
+
+	// bouncerRange how far bouncer looks for the next peak
+	bouncerRange     = 450.0
+	bouncerJumpSpeed = 15.0
+	bouncerRest      = 30
+
+	// shortSellerHover height above the player while hovering
+	shortSellerHover = 350.0
+	shortSellerSpeed = 5.0
+	// shortSellerAim player closer than this by X starts the drop
+	shortSellerAim  = 30.0
+	shortSellerRest = 90
+
+	// turretRange player closer than this by X is fired at
+	turretRange = 800.0
+	// turretFireTicks ticks between shots at aggression 1
+	turretFireTicks = 90
+
+	projectileSpeed  = 6.0
+	projectileRadius = 8.0
+	projectileLife   = 240
+
+	// enemySpawnRepeat spawn table repeats every enemySpawnRepeat pixels of the level
+	enemySpawnRepeat = 8000.0
+	// enemySpawnStart no enemies from spawn table closer than this to the start point
+	enemySpawnStart = 1500.0
+)
+
+// short seller states
+const (
+	shortSellerHovering = iota
+	shortSellerDropping
+	shortSellerResting
+)
+
+// Enemy enemy ball, the state is saved in LevelEntities
+type Enemy struct {
+	Kind   string  `json:"kind"`
+	Pos    Vector  `json:"pos"`
+	Vel    Vector  `json:"vel"`
+	Radius float64 `json:"radius"`
+	// Cooldown ticks until the next jump or shot
+	Cooldown int `json:"cooldown,omitempty"`
+	// State state of short seller
+	State int `json:"state,omitempty"`
+
+	// groundNormal average normal of touched segments, zero in the air
+	groundNormal Vector
+	// angle rotation of rolling ball
+	angle float64
+}
+
+// Projectile shot of turret
+type Projectile struct {
+	pos  Vector
+	vel  Vector
+	life int
+}
+
+// enemySpawn kind of enemy and distance from the start of the spawn table
+type enemySpawn struct {
+	kind     string
+	distance float64
+}
+
+// enemySpawnTables enemies which are placed along the level, the chaser is always added
+var enemySpawnTables = map[int][]enemySpawn{
+	Easy: {
+		{enemyBouncer, 2000},
+		{enemyTurret, 6000},
+	},
+	Medium: {
+		{enemyBouncer, 1500},
+		{enemyTurret, 3500},
+		{enemyShortSeller, 5500},
+	},
+	Difficult: {
+		{enemyBouncer, 1000},
+		{enemyShortSeller, 2500},
+		{enemyTurret, 4000},
+		{enemyBouncer, 5500},
+		{enemyTurret, 7000},
+	},
+}
+
+func NewEnemy(kind string, pos Vector) *Enemy {
+	return &Enemy{
+		Kind:   kind,
+		Radius: ballPhysicA.radius,
+		Pos:    pos,
+	}
+}
+
+// spawnEnemies chaser and enemies of spawn table after startX
+func (g *Game) spawnEnemies(startX float64) []*Enemy {
+	enemies := []*Enemy{NewEnemy(enemyChaser, Vector{-100, 0})}
+	if len(g.ground) == 0 {
+		return enemies
+	}
+
+	table := enemySpawnTables[g.score.CurrentDifficulty]
+	lastX := g.ground[len(g.ground)-1].B.X
+	for base := startX + enemySpawnStart; base < lastX; base += enemySpawnRepeat {
+		for _, spawn := range table {
+			x := base + spawn.distance
+			if x >= lastX {
+				break
+			}
+
+			pos := Vector{x, g.groundY(x) - ballPhysicA.radius*2}
+			if spawn.kind == enemyShortSeller {
+				pos.Y -= shortSellerHover
+			}
+			enemies = append(enemies, NewEnemy(spawn.kind, pos))
+		}
+	}
+
+	return enemies
+}
+
+// groundY Y of the chart at x
+func (g *Game) groundY(x float64) float64 {
+	i := sort.Search(len(g.ground), func(i int) bool {
+		return g.ground[i].B.X >= x
+	})
+	if i == len(g.ground) {
+		return g.ground[len(g.ground)-1].B.Y
+	}
+
+	seg := g.ground[i]
+	if seg.B.X == seg.A.X {
+		return seg.A.Y
+	}
+	t := math.Max(0, math.Min(1, (x-seg.A.X)/(seg.B.X-seg.A.X)))
+	return seg.A.Y + (seg.B.Y-seg.A.Y)*t
+}
+
+// updateEnemies move enemies and projectiles, it needs grid of current ground.
+// Enemies ahead of the ground buffer wait, enemies left behind are removed.
+func (g *Game) updateEnemies() {
+	alive := g.enemies[:0]
+	for _, e := range g.enemies {
+		outside := !isCircleRectangleColl(e.Pos, e.Radius, *g.borderSquare)
+
+		if e.Kind == enemyChaser {
+			// respawn at the right edge if chaser left the area
+			if outside || e.Pos.X-e.Radius < g.borderSquare.leftX() {
+				e.Pos = g.borderSquare.drawRight.B.Sub(Vector{e.Radius * 2, e.Radius * 2})
+				e.Vel = Vector{}
+			}
+		} else if e.Pos.X-e.Radius > g.borderSquare.rightX() {
+			alive = append(alive, e)
+			continue
+		} else if outside {
+			continue
+		}
+
+		g.updateEnemy(e)
+		alive = append(alive, e)
+	}
+	g.enemies = alive
+
+	g.updateProjectiles()
+}
+
+// updateEnemy move one enemy by its kind
+func (g *Game) updateEnemy(e *Enemy) {
+	gravity := enemyGravity
+	switch e.Kind {
+	case enemyChaser:
+		e.chase(g.ball.pos)
+	case enemyBouncer:
+		g.bounce(e)
+	case enemyShortSeller:
+		gravity = e.shortSell(g.ball.pos)
+	case enemyTurret:
+		g.fire(e)
+	}
+
+	e.Vel.Y = math.Min(e.Vel.Y+gravity, enemyMaxFall)
+	e.Pos = e.Pos.Add(e.Vel)
+	e.angle += e.Vel.X / e.Radius
+
+	margin := collisionQueryMargin(e.Radius)
+	g.nearSegments = g.grid.query(e.Pos.X-margin, e.Pos.X+margin, g.nearSegments[:0])
+	e.collide(g.nearSegments)
+}
+
+// chase accelerate to the target and jump if the target is above or the way is blocked,
+// enemyAggression scales speed and how often enemy jumps
+func (e *Enemy) chase(target Vector) {
+	dx := target.X - e.Pos.X
+	dir := 1.0
+	if dx < 0 {
+		dir = -1
+	}
+
+	onGround := e.groundNormal != (Vector{})
+	accel := enemyAccel * enemyAggression
+	if onGround {
+		// roll along ground
+		tangent := Vector{-e.groundNormal.Y, e.groundNormal.X}
+		e.Vel = e.Vel.Add(tangent.Mul(dir * accel)).Mul(enemyFriction)
+	} else {
+		e.Vel.X += dir * accel * enemyAirControl
+	}
+
+	maxSpeed := enemyMaxSpeed * enemyAggression
+	e.Vel.X = math.Max(-maxSpeed, math.Min(maxSpeed, e.Vel.X))
+
+	if e.Cooldown > 0 {
+		e.Cooldown--
+		return
+	}
+	if !onGround {
+		return
+	}
+
+	targetAbove := math.Abs(dx) < enemyJumpRange && target.Y < e.Pos.Y-enemyJumpHeight
+	blocked := math.Abs(e.Vel.X) < enemyStuckSpeed && math.Abs(dx) > e.Radius
+	if (targetAbove || blocked) && rand.Float64() < enemyJumpChance*enemyAggression {
+		e.Vel.Y = -enemyJumpSpeed
+		e.Cooldown = enemyJumpCooldown
+	}
+}
+
+// bounce hop to the highest point of ground near the enemy in direction of the player
+func (g *Game) bounce(e *Enemy) {
+	onGround := e.groundNormal != (Vector{})
+	if !onGround {
+		return
+	}
+
+	// stop on ground
+	e.Vel = e.Vel.Mul(0.5)
+	if e.Cooldown > 0 {
+		e.Cooldown--
+		return
+	}
+
+	dir := 1.0
+	if g.ball.pos.X < e.Pos.X {
+		dir = -1
+	}
+
+	// find peak
+	minX, maxX := e.Pos.X+dir*e.Radius, e.Pos.X+dir*bouncerRange
+	if dir < 0 {
+		minX, maxX = maxX, minX
+	}
+	peak := Vector{e.Pos.X + dir*bouncerRange/2, e.Pos.Y}
+	g.nearSegments = g.grid.query(minX, maxX, g.nearSegments[:0])
+	for _, seg := range g.nearSegments {
+		if seg.IsMovingWall || seg.IsRisingFloor || seg.isBorder {
+			continue
+		}
+		for _, p := range []Vector{seg.A, seg.B} {
+			if p.X >= minX && p.X <= maxX && p.Y < peak.Y {
+				peak = p
+			}
+		}
+	}
+
+	// higher peak needs stronger jump, flight time is for jump speed up and down
+	speed := bouncerJumpSpeed + math.Sqrt(math.Max(0, e.Pos.Y-peak.Y)*2*enemyGravity)*0.5
+	flight := 2 * speed / enemyGravity
+	e.Vel = Vector{(peak.X - e.Pos.X) / flight, -speed}
+	e.Cooldown = int(bouncerRest / enemyAggression)
+}
+
+// shortSell hover above the target, drop on it and rise again, return gravity for the state
+func (e *Enemy) shortSell(target Vector) float64 {
+	switch e.State {
+	case shortSellerHovering:
+		dx := target.X - e.Pos.X
+		speed := shortSellerSpeed * enemyAggression
+		e.Vel.X = math.Max(-speed, math.Min(speed, dx*0.05))
+		e.Vel.Y = (target.Y - shortSellerHover - e.Pos.Y) * 0.05
+
+		if math.Abs(dx) < shortSellerAim {
+			e.State = shortSellerDropping
+			e.Vel = Vector{}
+		}
+		return 0
+	case shortSellerDropping:
+		if e.groundNormal != (Vector{}) {
+			e.State = shortSellerResting
+			e.Cooldown = shortSellerRest
+		}
+		return enemyGravity * 2
+	default:
+		e.Vel.X *= 0.8
+		e.Cooldown--
+		if e.Cooldown <= 0 {
+			e.State = shortSellerHovering
+		}
+		return enemyGravity
+	}
+}
+
+// fire shoot at the player when it is in range
+func (g *Game) fire(e *Enemy) {
+	e.Vel.X *= 0.8
+	if e.Cooldown > 0 {
+		e.Cooldown--
+		return
+	}
+	if math.Abs(g.ball.pos.X-e.Pos.X) > turretRange {
+		return
+	}
+
+	dir := g.ball.pos.Sub(e.Pos).Normalize()
+	g.projectiles = append(g.projectiles, Projectile{
+		pos:  e.Pos.Add(dir.Mul(e.Radius)),
+		vel:  dir.Mul(projectileSpeed),
+		life: projectileLife,
+	})
+	e.Cooldown = int(turretFireTicks / enemyAggression)
+}
+
+// updateProjectiles move projectiles, projectiles hitting ground disappear
+func (g *Game) updateProjectiles() {
+	alive := g.projectiles[:0]
+	for _, p := range g.projectiles {
+		p.pos = p.pos.Add(p.vel)
+		p.life--
+		if p.life <= 0 {
+			continue
+		}
+
+		hit := false
+		g.nearSegments = g.grid.query(p.pos.X-projectileRadius, p.pos.X+projectileRadius, g.nearSegments[:0])
+		for _, seg := range g.nearSegments {
+			if p.pos.Sub(closestPointOnSegment(seg.A, seg.B, p.pos)).Len() < projectileRadius {
+				hit = true
+				break
+			}
+		}
+		if hit {
+			g.particles.burst(p.pos, 6, 3, 20, wallColor)
+			continue
+		}
+
+		alive = append(alive, p)
+	}
+	g.projectiles = alive
+}
+
+// collide push enemy out of ground, walls, floor and borders don't stop enemy
+func (e *Enemy) collide(segments []*Segment) {
+	e.groundNormal = Vector{}
+
+	for _, seg := range segments {
+		if seg.IsMovingWall || seg.IsRisingFloor || seg.isBorder {
+			continue
+		}
+
+		closest := closestPointOnSegment(seg.A, seg.B, e.Pos)
+		distVec := e.Pos.Sub(closest)
+		dist := distVec.Len()
+		if dist >= e.Radius || dist == 0 {
+			continue
+		}
+
+		normal := distVec.Mul(1 / dist)
+		e.Pos = e.Pos.Add(normal.Mul(e.Radius - dist))
+		if velDot := e.Vel.Dot(normal); velDot < 0 {
+			e.Vel = e.Vel.Sub(normal.Mul(velDot))
+		}
+		e.groundNormal = e.groundNormal.Add(normal)
+	}
+
+	e.groundNormal = e.groundNormal.Normalize()
+}
+
+// drawEnemies draw visible enemies and projectiles
+func (g *Game) drawEnemies(screen *ebiten.Image) {
+	for _, e := range g.enemies {
+		if g.camera.isVisibleX(e.Pos.X-e.Radius, e.Pos.X+e.Radius) {
+			e.draw(screen, g)
+		}
+	}
+
+	for _, p := range g.projectiles {
+		g.camera.drawCircle(screen, p.pos, projectileRadius, wallColorHover)
+	}
+}
+
+func (e *Enemy) draw(screen *ebiten.Image, g *Game) {
+	g.camera.drawCircle(screen, e.Pos, e.Radius, wallColor)
+
+	// details show kind of enemy
+	switch e.Kind {
+	case enemyChaser:
+		// spoke shows rolling
+		spoke := Vector{math.Cos(e.angle), math.Sin(e.angle)}.Mul(e.Radius * 0.7)
+		g.camera.strokeLine(screen, e.Pos.Sub(spoke), e.Pos.Add(spoke), 4, wallColorHover)
+	case enemyBouncer:
+		g.camera.drawCircle(screen, e.Pos, e.Radius*0.6, wallColorHover)
+		g.camera.drawCircle(screen, e.Pos, e.Radius*0.4, wallColor)
+	case enemyShortSeller:
+		// arrow down
+		g.camera.strokeLine(screen, e.Pos.Add(Vector{0, -e.Radius * 0.6}), e.Pos.Add(Vector{0, e.Radius * 0.6}), 4, wallColorHover)
+		g.camera.strokeLine(screen, e.Pos.Add(Vector{-e.Radius * 0.4, e.Radius * 0.2}), e.Pos.Add(Vector{0, e.Radius * 0.6}), 4, wallColorHover)
+		g.camera.strokeLine(screen, e.Pos.Add(Vector{e.Radius * 0.4, e.Radius * 0.2}), e.Pos.Add(Vector{0, e.Radius * 0.6}), 4, wallColorHover)
+	case enemyTurret:
+		// barrel aims at the player
+		dir := g.ball.pos.Sub(e.Pos).Normalize()
+		g.camera.strokeLine(screen, e.Pos, e.Pos.Add(dir.Mul(e.Radius*1.3)), 10, wallColorHover)
+	}
+
+	if g.settings.HazardPattern {
+		g.drawHazardCross(screen, e.Pos, e.Radius)
+	}
+}
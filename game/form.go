@@ -0,0 +1,223 @@
+package game
+
+import (
+	"ball/assets"
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	// bearPoundSpeed fall speed of bear ground pound
+	bearPoundSpeed = 18.0
+	// bullGlideSpeed max fall speed of gliding bull
+	bullGlideSpeed = 2.0
+	// stickyForce how strong sticky slime presses into ground
+	stickyForce = 0.5
+)
+
+// colors of forms, they are set by theme
+var (
+	bearColor   = color.RGBA{150, 100, 60, 255}
+	bullColor   = color.RGBA{220, 170, 40, 255}
+	stickyColor = color.RGBA{90, 200, 90, 255}
+)
+
+// shade darker color, f is part of brightness which is kept
+func shade(c color.RGBA, f float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(float64(c.R) * f),
+		G: uint8(float64(c.G) * f),
+		B: uint8(float64(c.B) * f),
+		A: c.A,
+	}
+}
+
+// Form shape of the slime with own physics, controls and look.
+// A new form is added to forms, physics of the form is made from ballPhysicA.
+type Form struct {
+	Name string
+	// key select the form
+	key ebiten.Key
+	// unlockScore score which unlocks the form
+	unlockScore int
+
+	// modify make physics of the form from normal physics
+	modify func(p BallPhysic) BallPhysic
+	// physic current physics, it is updated when presets change
+	physic BallPhysic
+
+	color func() color.RGBA
+	// update own controls, called every tick after common controls
+	update func(b *Ball, game *Game)
+	// decorate draw details over the slime body
+	decorate func(screen *ebiten.Image, camera *Camera, center Vector, radius float64)
+
+	// canInflate Shift inflates the ball
+	canInflate bool
+	// smashRed red segments break instead of taking score
+	smashRed bool
+	// climb no limit of slope angle
+	climb bool
+}
+
+var slimeForm = &Form{
+	Name:       "SLIME",
+	key:        ebiten.Key1,
+	modify:     func(p BallPhysic) BallPhysic { return p },
+	color:      func() color.RGBA { return ballColor },
+	canInflate: true,
+}
+
+// bearForm heavy, pounds the ground and smashes red segments
+var bearForm = &Form{
+	Name:        "BEAR",
+	key:         ebiten.Key2,
+	unlockScore: 500,
+	modify: func(p BallPhysic) BallPhysic {
+		p.gravity *= 1.3
+		p.speedRun *= 0.8
+		p.radius *= 1.15
+		p.jumpForce *= 0.9
+		return p
+	},
+	color: func() color.RGBA { return bearColor },
+	update: func(b *Ball, game *Game) {
+		// ground pound
+		if inpututil.IsKeyJustPressed(ebiten.KeyDown) && b.groundNormal == (Vector{}) {
+			b.vel.Y = math.Max(b.vel.Y, bearPoundSpeed)
+		}
+	},
+	decorate: func(screen *ebiten.Image, camera *Camera, center Vector, radius float64) {
+		// ears
+		for _, side := range []float64{-1, 1} {
+			ear := center.Add(Vector{side * radius * 0.65, -radius * 0.75})
+			camera.drawCircle(screen, ear, radius*0.3, bearColor)
+			camera.drawCircle(screen, ear, radius*0.15, shade(bearColor, 0.6))
+		}
+	},
+	smashRed: true,
+}
+
+// bullForm light, glides while Space is held
+var bullForm = &Form{
+	Name:        "BULL",
+	key:         ebiten.Key3,
+	unlockScore: 1000,
+	modify: func(p BallPhysic) BallPhysic {
+		p.gravity *= 0.5
+		p.speedRun *= 1.2
+		return p
+	},
+	color: func() color.RGBA { return bullColor },
+	update: func(b *Ball, game *Game) {
+		// glide
+		if ebiten.IsKeyPressed(ebiten.KeySpace) && b.vel.Y > bullGlideSpeed {
+			b.vel.Y = bullGlideSpeed
+		}
+	},
+	decorate: func(screen *ebiten.Image, camera *Camera, center Vector, radius float64) {
+		// horns
+		for _, side := range []float64{-1, 1} {
+			base := center.Add(Vector{side * radius * 0.5, -radius * 0.8})
+			tip := base.Add(Vector{side * radius * 0.4, -radius * 0.4})
+			camera.strokeLine(screen, base, tip, float32(radius*0.15), eyeColor)
+		}
+	},
+}
+
+// stickyForm sticks to ground and crawls up any slope
+var stickyForm = &Form{
+	Name:        "STICKY",
+	key:         ebiten.Key4,
+	unlockScore: 1500,
+	modify: func(p BallPhysic) BallPhysic {
+		p.speedRun *= 0.8
+		return p
+	},
+	color: func() color.RGBA { return stickyColor },
+	update: func(b *Ball, game *Game) {
+		if b.groundNormal == (Vector{}) {
+			return
+		}
+
+		// cancel gravity and press into ground
+		b.vel.Y -= b.currPhyState.gravity
+		b.vel = b.vel.Sub(b.groundNormal.Mul(stickyForce))
+
+		// crawl along ground
+		tangent := Vector{-b.groundNormal.Y, b.groundNormal.X}
+		if ebiten.IsKeyPressed(ebiten.KeyRight) {
+			b.vel = b.vel.Add(tangent.Mul(b.currPhyState.speedRun))
+		}
+		if ebiten.IsKeyPressed(ebiten.KeyLeft) {
+			b.vel = b.vel.Sub(tangent.Mul(b.currPhyState.speedRun))
+		}
+	},
+	decorate: func(screen *ebiten.Image, camera *Camera, center Vector, radius float64) {
+		// drips
+		for _, x := range []float64{-0.5, 0.1, 0.45} {
+			camera.drawCircle(screen, center.Add(Vector{x * radius, radius * 0.95}), radius*0.12, stickyColor)
+		}
+	},
+	climb: true,
+}
+
+// forms all forms in order of keys
+var forms = []*Form{slimeForm, bearForm, bullForm, stickyForm}
+
+func init() {
+	updateFormPhysics()
+}
+
+// updateFormPhysics make physics of forms from current ballPhysicA
+func updateFormPhysics() {
+	for _, f := range forms {
+		f.physic = f.modify(ballPhysicA)
+	}
+}
+
+// isFormUnlocked check if score is enough for the form
+func (g *Game) isFormUnlocked(f *Form) bool {
+	return g.score.getScore() >= f.unlockScore
+}
+
+// updateForm change form by keys
+func (g *Game) updateForm() {
+	for _, f := range forms {
+		if !inpututil.IsKeyJustPressed(f.key) || f == g.ball.form || !g.isFormUnlocked(f) {
+			continue
+		}
+
+		g.ball.form = f
+		g.particles.burst(g.ball.pos, 20, 5, 30, f.color())
+	}
+}
+
+// drawForms draw keys of forms, locked forms show score to unlock
+func (g *Game) drawForms(screen *ebiten.Image) {
+	y := 50.0
+	for i, f := range forms {
+		label := fmt.Sprintf("%d %s", i+1, f.Name)
+		clr := color.RGBA{120, 120, 120, 255}
+		if !g.isFormUnlocked(f) {
+			label = fmt.Sprintf("%d %d$", i+1, f.unlockScore)
+		} else if f == g.ball.form {
+			clr = f.color()
+		} else {
+			clr = color.RGBA{220, 220, 220, 255}
+		}
+
+		uiFillCircle(screen, 20, float32(y)+12, 8, clr)
+		options := &text.DrawOptions{}
+		options.GeoM.Translate(36, y)
+		options.ColorScale.ScaleWithColor(clr)
+		uiDrawText(screen, label, assets.ScoreFace, options)
+
+		y += 32
+	}
+}
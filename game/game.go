@@ -9,7 +9,7 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,7 +19,6 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/text/v2"
 
-	"github.com/hajimehoshi/ebiten/v2/vector"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 )
@@ -60,7 +59,7 @@ const (
 	redSegmentSpawn_EASY    = 60
 	movWallSpeedHight_EASY  = 13.0
 	movWallSpeedSlow_EASY   = 2.0
-	enemyBallSlow_EASY      = 0.5
+	enemyAggression_EASY    = 0.6
 )
 
 // MEDIUM
@@ -72,7 +71,7 @@ const (
 	redSegmentSpawn_MEDIUM    = 50
 	movWallSpeedHight_MEDIUM  = 15.0
 	movWallSpeedSlow_MEDIUM   = 3.0
-	enemyBallSlow_MEDIUM      = 0.7
+	enemyAggression_MEDIUM    = 0.8
 )
 
 // DIFFICULT
@@ -84,7 +83,7 @@ const (
 	redSegmentSpawn_DIFFICULT    = 30
 	movWallSpeedHight_DIFFICULT  = 16.0
 	movWallSpeedSlow_DIFFICULT   = 4.0
-	enemyBallSlow_DIFFICULT      = 0.8
+	enemyAggression_DIFFICULT    = 1.0
 )
 
 var (
@@ -102,8 +101,8 @@ var (
 	movWallSpeedHight = movWallSpeedHight_EASY
 	// movWallSpeedSlow speed Slow
 	movWallSpeedSlow = movWallSpeedSlow_EASY
-	// enemyBallSlow measure of slowing down, the smaller the slower
-	enemyBallSlow = enemyBallSlow_EASY
+	// enemyAggression 0..1 how fast enemy chases and how often it jumps
+	enemyAggression = enemyAggression_EASY
 )
 
 // Draw variables
@@ -119,7 +118,6 @@ var (
 	yellowColor              = color.RGBA{200, 100, 0, 255}
 	yellowColorHover         = color.RGBA{220, 120, 20, 255}
 	segmentWidth     float32 = 5
-	fractionsRadius          = 10
 )
 
 // Game states
@@ -129,6 +127,9 @@ const (
 	StatePlaying
 	StateLoadingLevel
 	StateTermination
+	StateLevelDetail
+	StatePaused
+	StateSettings
 )
 
 type Game struct {
@@ -136,16 +137,36 @@ type Game struct {
 	groundBuff [2][]*Segment
 
 	ball         *Ball
-	enemyBall    *Ball
+	enemies      []*Enemy
+	projectiles  []Projectile
 	collisionSeg []Segment
 	camera       *Camera
 	score        *Score
-	fractions    []Vector
-	frameTimer   *Timer
+	particles    *Particles
+	deathTimer   *Timer
+
+	// batches draw ground with one call per color
+	chartBatch  strokeBatch
+	groundBatch strokeBatch
+	redBatch    strokeBatch
+
+	// grid index of ground for collisions, nearSegments result of the last query
+	grid         segmentGrid
+	nearSegments []*Segment
+	// smashed red segments broken by the bear, the ball passes them until it leaves them
+	smashed []*Segment
+
+	// terrain filled area under groundBuff
+	terrain [2]terrainMesh
+	// minimap whole chart, savePoints all save points of level
+	minimap    *ChartThumbnail
+	savePoints []*SavePoint
 
 	// Game data
 	levels       []*Level
 	currentLevel int
+	levelSelect  *LevelSelect
+	levelDetail  *LevelDetail
 
 	// Menu
 	menuFont     font.Face
@@ -154,13 +175,33 @@ type Game struct {
 	currentState int
 	menuBg       *ebiten.Image
 
+	// screen size, see Layout
+	screenWidth  float64
+	screenHeight float64
+
 	// wall
 	borderSquare *BorderSquare
 	movingWall   *Segment
+	// wallClosing the wall is faster than the ball, wallTicks ticks for warning blinking
+	wallClosing bool
+	wallTicks   int
+	// risingFloor margin call floor, nil if level doesn't have it
+	risingFloor *Segment
+	// lingerX, lingerTicks where and how long the player lingers
+	lingerX     float64
+	lingerTicks int
 
 	drawError error
 
 	difficulty int
+
+	// settings
+	settings            *Settings
+	settingsReturnState int
+	audio               *assets.Audio
+	musicIntensity      float64
+	// physics presets of ball physics
+	physics *Physics
 }
 
 func (g *Game) Update() error {
@@ -169,6 +210,9 @@ func (g *Game) Update() error {
 		return g.drawError
 	}
 
+	// music depends on state
+	g.updateMusic()
+
 	var err error
 	switch g.currentState {
 	case StateMenu:
@@ -180,30 +224,43 @@ func (g *Game) Update() error {
 			return ebiten.Termination
 		}
 	case StateLevelSelect:
-		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-			g.currentState = StateMenu
-		}
+		g.updateLevelSelect()
+	case StateLevelDetail:
+		g.updateLevelDetail()
 	case StateLoadingLevel:
 		// upload level
 		return g.uploadLevel()
 	case StatePlaying:
-		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-			g.getCurrentLevel().setMovingWall(g.movingWall)
-			g.getCurrentLevel().setEnemyBallPos(&g.enemyBall.pos)
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) && !g.ball.isDied {
+			g.currentState = StatePaused
+			return nil
+		}
 
-			return returnToSelectLevel(g)
+		// physics presets can be changed while game runs
+		err = g.updatePhysicsReload()
+		if err != nil {
+			return err
 		}
 
 		// game logic here
 		return g.gameUpdate()
+	case StatePaused:
+		g.updatePaused()
+	case StateSettings:
+		g.updateSettings()
 	}
 	return err
 }
 
 func (g *Game) gameUpdate() error {
 
-	// delete old fractions by timer
-	g.updateFrame()
+	// death animation, the simulation is stopped
+	if g.ball.isDied {
+		return g.updateDeath()
+	}
+
+	// update particles
+	g.particles.Update()
 	// update MovingWall
 	g.updateMovingWall()
 
@@ -211,31 +268,33 @@ func (g *Game) gameUpdate() error {
 	updateSavePointPosition(g.groundBuff[0])
 	updateSavePointPosition(g.groundBuff[1])
 
-	// update enemy
-	if g.enemyBall != nil {
-		g.enemyBall.pos = g.enemyBall.pos.Add(g.enemyBall.vel)
-	}
 	// fill Ground slice
 	groundFromBuff, lenBuff, middleSegment, lastXbuff := g.fillGround()
+	// rising floor when the player lingers
+	if g.getCurrentLevel().RisingFloor {
+		g.updateRisingFloor()
+		groundFromBuff = append(groundFromBuff, g.risingFloor)
+	}
+	// ground is in the grid since the buffer swap, only moving segments are put every tick
+	g.grid.setDynamic(groundFromBuff[lenBuff:])
 
 	// update player
+	g.updateForm()
 	g.ball.Update(groundFromBuff, g)
-	// update enemy
-	g.updateEnemy()
+	// update enemies
+	g.updateEnemies()
 	// check collisions and move objects
 	g.CheckCollisions(&g.collisionSeg, groundFromBuff)
 
-	// return if player is died
+	// start death animation if player is died
 	if g.ball.isDied {
-		g.getCurrentLevel().resetLevel()
-
-		err := saveLevel(g.getCurrentLevel())
-		if err != nil {
-			return err
-		}
-
-		return returnToSelectLevel(g)
+		g.audio.PlaySound(assets.SoundDeath)
+		g.particles.burst(g.ball.pos, 60, 12, 60, ballColor)
+		g.particles.burst(g.ball.pos, 30, 8, 60, wallColor)
+		g.camera.shake(1)
+		g.deathTimer.Reset()
 
+		return nil
 	}
 	// return if player is finished
 	if g.getCurrentLevel().getFinished() {
@@ -245,7 +304,12 @@ func (g *Game) gameUpdate() error {
 	g.updateGroundBuffer(middleSegment, lenBuff, lastXbuff)
 
 	// Update camera
-	g.camera.Update(g.ball.pos.X, g.ball.pos.Y)
+	if inpututil.IsKeyJustPressed(ebiten.KeyZ) {
+		g.camera.toggleZoom()
+	}
+	// wider screen shows more of the chart
+	g.camera.Width, g.camera.Height = g.screenWidth, g.screenHeight
+	g.camera.Update(g.ball.pos, g.ball.vel, g.ball.currPhyState.state == phyStateB)
 
 	return nil
 }
@@ -266,6 +330,7 @@ func (g *Game) updateGroundBuffer(middleSegment *Segment, lenBuff int, lastXbuff
 		for i := 0; i < copySize; i++ {
 			g.groundBuff[1][i] = &g.ground[secondBuffI+i]
 		}
+		g.buildGrid()
 
 		// update wall
 		if g.movingWall.A.X > g.ball.pos.X {
@@ -275,6 +340,14 @@ func (g *Game) updateGroundBuffer(middleSegment *Segment, lenBuff int, lastXbuff
 	}
 }
 
+// buildGrid put segments of both ground buffers into the grid, called when buffers change
+func (g *Game) buildGrid() {
+	ground := make([]*Segment, 0, len(g.groundBuff[0])+len(g.groundBuff[1]))
+	ground = append(ground, g.groundBuff[0]...)
+	ground = append(ground, g.groundBuff[1]...)
+	g.grid.build(ground)
+}
+
 func (g *Game) fillGround() (groundFromBuff []*Segment, lenBuff int, middleSegment *Segment, lastXbuff float64) {
 
 	// fill unite slice from g.groundBuff[0] and g.groundBuff[1]
@@ -296,30 +369,23 @@ func (g *Game) fillGround() (groundFromBuff []*Segment, lenBuff int, middleSegme
 	return groundFromBuff, lenBuff, middleSegment, lastXbuff
 }
 
-// updateMovingWall update MovingWall
-func (g *Game) updateMovingWall() {
-	// increse speed if movingWall too far
-	distanceWallBall := math.Abs(g.ball.pos.X - g.movingWall.A.X)
-	if distanceWallBall > ScreenWidth {
-		g.movingWall.A.X += movWallSpeedHight
-		g.movingWall.B.X += movWallSpeedHight
-	} else {
-		g.movingWall.A.X += movWallSpeedSlow
-		g.movingWall.B.X += movWallSpeedSlow
+// updateDeath show death animation and return to level select
+func (g *Game) updateDeath() error {
+	g.particles.Update()
+	g.camera.Update(g.ball.pos, Vector{}, false)
+	g.deathTimer.Update()
+	if !g.deathTimer.IsReady() {
+		return nil
 	}
-}
 
-func (g *Game) updateFrame() {
-	g.frameTimer.Update()
-	if g.frameTimer.IsReady() {
-		g.frameTimer.Reset()
+	g.getCurrentLevel().resetLevel()
 
-		if len(g.fractions) > 20 {
-			g.fractions = g.fractions[20:len(g.fractions)]
-		} else if len(g.fractions) > 0 {
-			g.fractions = g.fractions[1:len(g.fractions)]
-		}
+	err := saveLevel(g.getCurrentLevel())
+	if err != nil {
+		return err
 	}
+
+	return returnToSelectLevel(g)
 }
 
 // saveCurrentLevel marshals level to json and save it in file
@@ -341,6 +407,11 @@ func (g *Game) saveCurrentLevel() error {
 }
 
 func (g *Game) uploadLevel() error {
+	// physics of level or difficulty
+	err := g.applyPhysics()
+	if err != nil {
+		return err
+	}
 
 	// Read and parse CSV data
 	groundPoints, err := readLevelCSV(filepath.Join(GameFilesDir, (g.getCurrentLevel().ChartFile)))
@@ -351,6 +422,12 @@ func (g *Game) uploadLevel() error {
 	// Create segments with save points
 	segments, maxX, maxY := g.createSegments(groundPoints)
 
+	// render minimap
+	if g.minimap != nil {
+		g.minimap.deallocate()
+	}
+	g.minimap = newChartThumbnail(groundPoints, minimapWidth, minimapHeight, 1, minimapBackground)
+
 	if (len(segments)) <= groundBuffSize*2 {
 		return fmt.Errorf("too small points for level")
 	}
@@ -376,6 +453,7 @@ func (g *Game) uploadLevel() error {
 func (g *Game) createSegments(points []Vector) ([]Segment, float64, float64) {
 	segments := make([]Segment, len(points)-1)
 	maxY := 0.0
+	g.savePoints = nil
 
 	redCount := 100
 
@@ -387,7 +465,7 @@ func (g *Game) createSegments(points []Vector) ([]Segment, float64, float64) {
 
 		if i%savePointSpawn == 0 {
 			startPosition := seg.GetPosWithMinY()
-			startPosition = startPosition.Sub(seg.Normal().Mul(15))
+			startPosition = startPosition.Sub(seg.Normal().Mul(savePointGroundOffset))
 
 			pos := startPosition
 			randInt := float64(rand.Intn(200))
@@ -399,8 +477,9 @@ func (g *Game) createSegments(points []Vector) ([]Segment, float64, float64) {
 					Y: pos.Y,
 				},
 				startPosition: startPosition,
-				Radius:        20,
+				Radius:        savePointRadius,
 			}
+			g.savePoints = append(g.savePoints, seg.savePoint)
 		}
 
 		// set red segment
@@ -430,8 +509,9 @@ func (g *Game) createSegments(points []Vector) ([]Segment, float64, float64) {
 		Position:      pos,
 		startPosition: pos,
 		IsFinish:      true,
-		Radius:        50,
+		Radius:        finishRadius,
 	}
+	g.savePoints = append(g.savePoints, segments[len(segments)-1].savePoint)
 
 	return segments, segments[len(segments)-1].B.X, maxY
 }
@@ -486,10 +566,17 @@ func (g *Game) initializeLevelState(segments []Segment, lastX, maxY float64) err
 		// delete savePoint from spawn
 		segments[savePointIndex].savePoint = nil
 
+		// save points behind are collected
+		for _, sp := range g.savePoints {
+			if sp.Position.X <= savePoint.Position.X {
+				sp.collected = true
+			}
+		}
+
 		if g.getCurrentLevel().getMovingWall() != nil {
 			g.movingWall = g.getCurrentLevel().getMovingWall()
 		}
-		if g.movingWall.A.X > savePoint.Position.X || g.movingWall.B.X > savePoint.Position.X || g.movingWall.B.Y > maxY-wallHeight {
+		if g.movingWall == nil || g.movingWall.A.X > savePoint.Position.X || g.movingWall.B.X > savePoint.Position.X || g.movingWall.B.Y > maxY-wallHeight {
 			g.movingWall = &Segment{
 				A:            Vector{g.groundBuff[0][0].A.X, 0},
 				B:            Vector{g.groundBuff[0][0].A.X, maxY - wallHeight},
@@ -501,15 +588,31 @@ func (g *Game) initializeLevelState(segments []Segment, lastX, maxY float64) err
 
 	}
 
+	// index of ground, collisions are checked only with segments near objects
+	g.buildGrid()
+	g.smashed = nil
+
+	// floor starts at the bottom if it is saved too close to the save point
+	g.risingFloor = g.getCurrentLevel().getRisingFloor()
+	if g.risingFloor != nil && g.risingFloor.A.Y < savePoint.Position.Y+risingFloorSafe {
+		g.risingFloor = nil
+	}
+	g.lingerX = savePoint.Position.X
+	g.lingerTicks = 0
+
 	// set ball and enemy
 	g.ball = NewBall(savePoint.Position)
 	g.currentState = StatePlaying
 
-	g.enemyBall = NewEnemyBall()
-	// set position if exist
+	g.projectiles = nil
+	g.enemies = g.getCurrentLevel().getEnemies()
+	if len(g.enemies) == 0 {
+		g.enemies = g.spawnEnemies(savePoint.Position.X)
 
-	if g.getCurrentLevel().getEnemyBallPos() != nil {
-		g.enemyBall.pos = *g.getCurrentLevel().getEnemyBallPos()
+		// the first enemy is the chaser
+		if pos := g.getCurrentLevel().getEnemyBallPos(); pos != nil {
+			g.enemies[0].Pos = *pos
+		}
 	}
 
 	return nil
@@ -521,92 +624,77 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		g.drawMenu(screen)
 	case StateLevelSelect:
 		g.drawLevelSelect(screen)
+	case StateLevelDetail:
+		g.drawLevelDetail(screen)
 	case StateLoadingLevel:
 		// draw loading
 	case StatePlaying:
 		g.drawPlaying(screen)
+	case StatePaused:
+		g.drawPaused(screen)
+	case StateSettings:
+		g.drawSettings(screen)
 	}
 }
 
 func (g *Game) drawPlaying(screen *ebiten.Image) {
 	screen.Fill(playBackground)
 
+	// Draw parallax layers
+	g.drawBackground(screen)
+
+	// Draw area under the chart
+	g.drawTerrain(screen)
+
 	// Draw borderSquare
 	borderSquare := g.borderSquare
 	if borderSquare != nil {
-		vector.StrokeLine(screen,
-			float32(g.borderSquare.top.A.X-g.camera.X),
-			float32(g.borderSquare.top.A.Y-g.camera.Y),
-			float32(g.borderSquare.top.B.X-g.camera.X),
-			float32(g.borderSquare.top.B.Y-g.camera.Y),
-			segmentWidth, yellowColor, false)
-		vector.StrokeLine(screen,
-			float32(g.borderSquare.drawRight.A.X-g.camera.X),
-			float32(g.borderSquare.drawRight.A.Y-g.camera.Y),
-			float32(g.borderSquare.drawRight.B.X-g.camera.X),
-			float32(g.borderSquare.drawRight.B.Y-g.camera.Y),
-			segmentWidth, yellowColor, false)
-		vector.StrokeLine(screen,
-			float32(g.borderSquare.bottom.A.X-g.camera.X),
-			float32(g.borderSquare.bottom.A.Y-g.camera.Y),
-			float32(g.borderSquare.bottom.B.X-g.camera.X),
-			float32(g.borderSquare.bottom.B.Y-g.camera.Y),
-			segmentWidth, yellowColor, false)
-		vector.StrokeLine(screen,
-			float32(g.borderSquare.drawLeft.A.X-g.camera.X),
-			float32(g.borderSquare.drawLeft.A.Y-g.camera.Y),
-			float32(g.borderSquare.drawLeft.B.X-g.camera.X),
-			float32(g.borderSquare.drawLeft.B.Y-g.camera.Y),
-			segmentWidth, yellowColor, false)
+		g.camera.strokeLine(screen, borderSquare.top.A, borderSquare.top.B, segmentWidth, yellowColor)
+		g.camera.strokeLine(screen, borderSquare.drawRight.A, borderSquare.drawRight.B, segmentWidth, yellowColor)
+		g.camera.strokeLine(screen, borderSquare.bottom.A, borderSquare.bottom.B, segmentWidth, yellowColor)
+		g.camera.strokeLine(screen, borderSquare.drawLeft.A, borderSquare.drawLeft.B, segmentWidth, yellowColor)
 	}
 
 	// Draw moving Wall
-	vector.StrokeLine(screen,
-		float32(g.movingWall.A.X-g.camera.X),
-		float32(g.movingWall.A.Y-g.camera.Y),
-		float32(g.movingWall.B.X-g.camera.X),
-		float32(g.movingWall.B.Y-g.camera.Y),
-		segmentWidth, wallColor, false)
+	g.camera.strokeLine(screen, g.movingWall.A, g.movingWall.B, segmentWidth, wallColor)
 
-	// Draw ground
-	for _, seg := range g.ground {
-		vector.StrokeLine(screen,
-			float32(seg.A.X-g.camera.X),
-			float32(seg.A.Y-g.camera.Y),
-			float32(seg.B.X-g.camera.X),
-			float32(seg.B.Y-g.camera.Y),
-			1, groundColor, false)
+	// Draw visible part of the whole chart
+	chart := visibleSegments(g.ground, g.camera)
+	for i := range chart {
+		g.chartBatch.add(&chart[i], g.camera)
 	}
+	g.chartBatch.draw(screen, g.camera.toScreenLen(1), groundColor)
 
-	drawGround(screen, g.groundBuff[0], g.camera)
-	drawGround(screen, g.groundBuff[1], g.camera)
+	// Draw ground
+	g.drawGround(screen, g.groundBuff[0])
+	g.drawGround(screen, g.groundBuff[1])
+	g.groundBatch.draw(screen, g.camera.toScreenLen(float64(segmentWidth)), groundColor)
+	g.redBatch.draw(screen, g.camera.toScreenLen(float64(segmentWidth)), yellowColor)
 
 	// Draw ball
-	ballColor := ballColor
-	if g.ball.currPhyState.state == phyStateB {
-		ballColor = ballColorBig
+	if !g.ball.isDied {
+		ballColor := g.ball.form.color()
+		if g.ball.currPhyState.state == phyStateB {
+			ballColor = ballColorBig
+		}
+		g.ball.drawSlime(screen, g.camera, ballColor)
 	}
-	vector.DrawFilledCircle(
-		screen,
-		float32(g.ball.pos.X-g.camera.X),
-		float32(g.ball.pos.Y-g.camera.Y),
-		float32(g.ball.radius), ballColor, false)
 
 	// Draw enemy
-	if g.enemyBall != nil {
-		vector.DrawFilledCircle(
-			screen,
-			float32(g.enemyBall.pos.X-g.camera.X),
-			float32(g.enemyBall.pos.Y-g.camera.Y),
-			float32(g.enemyBall.radius), wallColor, false)
-	}
+	g.drawEnemies(screen)
 
-	vector.StrokeLine(screen,
-		float32(g.enemyBall.pos.X-g.camera.X),
-		float32(g.camera.Y-g.camera.Y+ScreenHeight-100),
-		float32(g.enemyBall.pos.X-g.camera.X),
-		float32(g.camera.Y-g.camera.Y+ScreenHeight),
-		2, wallColor, false)
+	if g.settings.ShowEnemyMarker && g.borderSquare != nil {
+		for _, e := range g.enemies {
+			if e.Pos.X > g.borderSquare.rightX() {
+				continue
+			}
+			enemyX, _ := g.camera.toUI(e.Pos)
+			uiStrokeLine(screen,
+				float32(enemyX), float32(g.screenHeight-100),
+				float32(enemyX), float32(g.screenHeight),
+				2, wallColor)
+		}
+	}
 
 	// Draw collisions
 	// for _, seg := range g.collisionSeg {
@@ -620,63 +708,61 @@ func (g *Game) drawPlaying(screen *ebiten.Image) {
 	// 	vector.DrawFilledCircle(screen,
 	// 		float32(seg.closestPoint.X-g.camera.X),
 	// 		float32(seg.closestPoint.Y-g.camera.Y),
-	// 		float32(fractionsRadius), fractionsColor, false)
+	// 		float32(particleRadius), ballColor, false)
 
 	// }
 
-	for _, fr := range g.fractions {
-		vector.DrawFilledCircle(screen,
-			float32(fr.X-g.camera.X),
-			float32(fr.Y-g.camera.Y),
-			float32(fractionsRadius), ballColor, false)
+	// Draw particles
+	g.particles.Draw(screen, g.camera)
+
+	// Draw minimap
+	if g.settings.ShowMinimap {
+		g.drawMinimap(screen)
 	}
 
 	// Draw score
-	options := &text.DrawOptions{}
-	options.GeoM.Translate(10, 10)
-	options.ColorScale.ScaleWithColor(color.White)
-	text.Draw(screen, fmt.Sprintf("Level score: %d$", g.getCurrentLevel().Score.getScore()), assets.ScoreFace, options)
+	g.drawRisingFloor(screen)
+	g.drawWallWarning(screen)
+
+	if g.settings.ShowScore {
+		options := &text.DrawOptions{}
+		options.GeoM.Translate(10, 10)
+		options.ColorScale.ScaleWithColor(color.White)
+		uiDrawText(screen, fmt.Sprintf("Level score: %d$", g.getCurrentLevel().Score.getScore()), assets.ScoreFace, options)
 
-	// Draw return button
-	g.drawReturnButton(screen, StateLevelSelect)
+		g.drawForms(screen)
+	}
+
+	// Draw pause button
+	g.drawReturnButton(screen, StatePaused)
 }
 
-func drawGround(screen *ebiten.Image, ground []*Segment, camera *Camera) {
+// drawGround draw save points and add visible segments to batches
+func (g *Game) drawGround(screen *ebiten.Image, ground []*Segment) {
+	camera := g.camera
 	for _, seg := range ground {
+		if !camera.isVisibleX(math.Min(seg.A.X, seg.B.X), math.Max(seg.A.X, seg.B.X)) {
+			continue
+		}
+
 		if seg.savePoint != nil {
-			vector.DrawFilledCircle(screen,
-				float32(seg.savePoint.Position.X-camera.X),
-				float32(seg.savePoint.Position.Y-camera.Y),
-				float32(seg.savePoint.Radius),
-				savePointColor, false)
+			camera.drawCircle(screen, seg.savePoint.Position, seg.savePoint.Radius, savePointColor)
 			if seg.savePoint.IsFinish {
-				vector.DrawFilledCircle(screen,
-					float32(seg.savePoint.Position.X-camera.X),
-					float32(seg.savePoint.Position.Y-camera.Y),
-					float32(seg.savePoint.Radius*0.7),
-					color.Black, false)
+				camera.drawCircle(screen, seg.savePoint.Position, seg.savePoint.Radius*0.7, color.Black)
 			}
 		}
 
-		color := groundColor
-		if seg.isRed {
-			color = yellowColor
+		if seg.isRed && g.settings.HazardPattern {
+			g.redBatch.addDashed(seg, camera, hazardDash, hazardGap)
+		} else if seg.isRed {
+			g.redBatch.add(seg, camera)
+		} else {
+			g.groundBatch.add(seg, camera)
 		}
-
-		vector.StrokeLine(screen,
-			float32(seg.A.X-camera.X),
-			float32(seg.A.Y-camera.Y),
-			float32(seg.B.X-camera.X),
-			float32(seg.B.Y-camera.Y),
-			segmentWidth, color, false)
 	}
 }
 
-func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return ScreenWidth, ScreenHeight
-}
-
-func NewGame() (*Game, error) {
+func NewGame(settings *Settings, audio *assets.Audio) (*Game, error) {
 
 	// Menu
 	// Load fonts
@@ -695,6 +781,13 @@ func NewGame() (*Game, error) {
 		Size: 24, DPI: 72, Hinting: font.HintingFull,
 	})
 
+	// user themes, the theme is needed for menu background
+	err = LoadThemes()
+	if err != nil {
+		return nil, err
+	}
+	setTheme(findTheme(settings.Theme))
+
 	// Create menu background
 	menuBg := ebiten.NewImage(ScreenWidth, ScreenHeight)
 	menuBg.Fill(playBackground)
@@ -716,6 +809,12 @@ func NewGame() (*Game, error) {
 	// set variables depending on the Difficulty
 	setDifficultyVars(score.CurrentDifficulty)
 
+	// physics presets from file or default presets
+	physics, err := LoadPhysics()
+	if err != nil {
+		return nil, err
+	}
+
 	for _, e := range dirEntry {
 		// find levels
 		if strings.Contains(e.Name(), ".json") {
@@ -729,6 +828,11 @@ func NewGame() (*Game, error) {
 				return nil, err
 			}
 
+			// settings and other data files are not levels
+			if level.Ticker == "" || level.ChartFile == "" {
+				continue
+			}
+
 			if level.Score == nil {
 				level.Score = newScore()
 			}
@@ -743,14 +847,16 @@ func NewGame() (*Game, error) {
 		}
 	}
 
+	// order levels by number
+	sortLevels(levels)
+
 	game := &Game{
-		frameTimer: NewTimer(80 * time.Millisecond),
+		particles:  newParticles(),
+		deathTimer: NewTimer(time.Second),
 		score:      score,
+		physics:    physics,
 
-		camera: &Camera{
-			Width:  float64(ScreenWidth),
-			Height: float64(ScreenHeight),
-		},
+		camera: newCamera(),
 
 		// menu
 		menuFont:     menuFont,
@@ -758,34 +864,39 @@ func NewGame() (*Game, error) {
 		buttonFont:   buttonFont,
 		currentState: StateMenu,
 		menuBg:       menuBg,
+		screenWidth:  ScreenWidth,
+		screenHeight: ScreenHeight,
 		levels:       levels,
+		levelSelect:  &LevelSelect{},
+		settings:     settings,
+		audio:        audio,
 	}
 
 	return game, nil
 }
 
 func (g *Game) drawMenu(screen *ebiten.Image) {
-	screen.DrawImage(g.menuBg, nil)
+	g.drawMenuBackground(screen)
 
 	// Draw title
 	title := "STOCK JUMPER"
 	w, h := text.Measure(title, assets.ScoreFaceBig, 0)
 	options := &text.DrawOptions{}
-	options.GeoM.Translate((ScreenWidth)/2-w/2, 130-h/2)
+	options.GeoM.Translate(g.uiCenterX()-w/2, 130-h/2)
 	options.ColorScale.ScaleWithColor(color.White)
-	text.Draw(screen, title, assets.ScoreFaceBig, options)
+	uiDrawText(screen, title, assets.ScoreFaceBig, options)
 
 	// Create and draw buttons
 	buttons := []Button{
 		{
-			X: ScreenWidth/2 - 200, Y: 200, Width: 200, Height: 60,
+			X: g.uiCenterX() - 200, Y: 200, Width: 200, Height: 60,
 			Text:       "PLAY",
 			Color:      ballColor,
 			HoverColor: ballColorBig,
 			Action:     func() { g.currentState = StateLevelSelect },
 		},
 		{
-			X: ScreenWidth / 2, Y: 200, Width: 200, Height: 60,
+			X: g.uiCenterX(), Y: 200, Width: 200, Height: 60,
 			Text:       getDifficultName(g.score.CurrentDifficulty),
 			Color:      getDifficultColor(g.score.CurrentDifficulty),
 			HoverColor: getDifficultColorHower(g.score.CurrentDifficulty),
@@ -794,14 +905,21 @@ func (g *Game) drawMenu(screen *ebiten.Image) {
 			},
 		},
 		{
-			X: ScreenWidth/2 - 200, Y: 200, Width: 200, Height: 60,
+			X: g.uiCenterX() - 200, Y: 200, Width: 200, Height: 60,
 			Text:       "PLAY",
 			Color:      ballColor,
 			HoverColor: ballColorBig,
 			Action:     func() { g.currentState = StateLevelSelect },
 		},
 		{
-			X: ScreenWidth/2 - 100, Y: 280, Width: 200, Height: 60,
+			X: g.uiCenterX() - 100, Y: 280, Width: 200, Height: 60,
+			Text:       "SETTINGS",
+			Color:      groundColor,
+			HoverColor: groundColorHover,
+			Action:     g.openSettings,
+		},
+		{
+			X: g.uiCenterX() - 100, Y: 360, Width: 200, Height: 60,
 			Text:       "QUIT",
 			Color:      wallColor,
 			HoverColor: wallColorHover,
@@ -818,91 +936,9 @@ func (g *Game) drawMenu(screen *ebiten.Image) {
 
 }
 
-func (g *Game) drawLevelSelect(screen *ebiten.Image) {
-	// Background color
-	screen.DrawImage(g.menuBg, nil)
-
-	// Draw title
-	title := "SELECT LEVEL"
-	w, h := text.Measure(title, assets.ScoreFaceBig, 0)
-	options := &text.DrawOptions{}
-	options.GeoM.Translate(ScreenWidth/2-w/2, 50-h/2)
-	options.ColorScale.ScaleWithColor(color.White)
-	text.Draw(screen, title, assets.ScoreFaceBig, options)
-
-	options2 := &text.DrawOptions{}
-	options2.GeoM.Translate(200, 70)
-	options2.ColorScale.ScaleWithColor(color.White)
-
-	// Draw score
-	score := fmt.Sprintf("Score: %s$", strconv.Itoa(g.score.getScore()))
-	text.Draw(screen, score, assets.ScoreFace, options2)
-
-	// Draw levels
-	levelButtons := make([]Button, len(g.levels))
-	sellLevel := make([]Button, len(g.levels))
-	for i, level := range g.levels {
-		levelButtons[i] = Button{
-			X: 200, Y: 150 + float64(i)*80, Width: 400, Height: 60,
-			Text:       level.Name,
-			Color:      groundColor,
-			HoverColor: groundColorHover,
-			Action: func(lvlIdx int) func() {
-				return func() {
-					if !g.levels[lvlIdx].getFinished() {
-						g.currentLevel = lvlIdx
-						g.currentState = StateLoadingLevel
-					}
-				}
-			}(i),
-		}
-
-		sellLevelBtnCol := groundColor
-		sellLevelBtnColHover := groundColorHover
-		if level.Score.getScore() > 0 {
-			sellLevelBtnCol = ballColor
-			sellLevelBtnColHover = ballColorBig
-		}
-
-		text := fmt.Sprintf("%d$", level.Score.getScore())
-
-		if level.getFinished() {
-			text = fmt.Sprintf("%d(x%d)$", level.Score.getScore(), finishLevelSell)
-		}
-
-		sellLevel[i] = Button{
-			X: levelButtons[i].X + levelButtons[i].Width + 10, Y: 150 + float64(i)*80, Width: 350, Height: 60,
-			Text:       text,
-			Color:      sellLevelBtnCol,
-			HoverColor: sellLevelBtnColHover,
-			Action: func(lvlIdx int) func() {
-				return func() {
-					err := resetLevel(g.levels[lvlIdx], g)
-					g.drawError = err
-				}
-			}(i),
-		}
-
-		// Draw level button
-		drawProgressButton(screen, &levelButtons[i], level)
-		if levelButtons[i].IsClicked() {
-			levelButtons[i].Action()
-		}
-
-		// Draw sell level
-		drawButtonText(screen, &sellLevel[i])
-		if sellLevel[i].IsClicked() {
-			sellLevel[i].Action()
-		}
-	}
-
-	// Draw return button
-	g.drawReturnButton(screen, StateMenu)
-}
-
 func (g *Game) drawReturnButton(screen *ebiten.Image, returnState int) {
 	btn := Button{
-		X: 10, Y: ScreenHeight - 70, Width: 60, Height: 60,
+		X: 10, Y: g.screenHeight - 70, Width: 60, Height: 60,
 		Color:      groundColor,
 		HoverColor: groundColorHover,
 		Action: func() {
@@ -911,9 +947,9 @@ func (g *Game) drawReturnButton(screen *ebiten.Image, returnState int) {
 	}
 
 	// Check hover state
-	mx, my := ebiten.CursorPosition()
-	hover := float64(mx) > btn.X && float64(mx) < btn.X+btn.Width &&
-		float64(my) > btn.Y && float64(my) < btn.Y+btn.Height
+	mx, my := uiCursorPosition()
+	hover := mx > btn.X && mx < btn.X+btn.Width &&
+		my > btn.Y && my < btn.Y+btn.Height
 
 	// Choose color
 	btnColor := btn.Color
@@ -924,12 +960,12 @@ func (g *Game) drawReturnButton(screen *ebiten.Image, returnState int) {
 	}
 
 	// Draw button
-	vector.DrawFilledRect(screen,
+	uiFillRect(screen,
 		float32(btn.X),
 		float32(btn.Y),
 		float32(btn.Width),
 		float32(btn.Height),
-		btnColor, false)
+		btnColor)
 
 	tex := ebiten.NewImage(1, 1)
 	for y := 0; y < 1; y++ {
@@ -977,6 +1013,7 @@ func (g *Game) drawReturnButton(screen *ebiten.Image, returnState int) {
 	indices := []uint16{0, 1, 2}
 
 	// Draw the triangle
+	uiVertices(vertices)
 	screen.DrawTriangles(vertices, indices, tex, &ebiten.DrawTrianglesOptions{
 		AntiAlias: true, // Smooth edges
 	})
@@ -991,11 +1028,6 @@ func (g *Game) getCurrentLevel() *Level {
 	return g.levels[g.currentLevel]
 }
 
-func (g *Game) updateEnemy() {
-	g.enemyBall.vel.X *= enemyBallSlow
-	g.enemyBall.vel.Y *= enemyBallSlow
-}
-
 // CheckCollisions check collisions and move objects
 func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 	// average normal
@@ -1003,8 +1035,7 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 	collisionSeg := []Segment{}
 	var penetrationSum float64
 	wallThickness := 3.0 // to avoid falling into a segment
-	minVec := math.MaxFloat64
-	velEnemy := Vector{}
+	touchRed := false
 
 	if !isCircleRectangleColl(game.ball.pos, game.ball.radius, *game.borderSquare) {
 		game.ball.vel = Vector{}
@@ -1017,37 +1048,50 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 		} else {
 			game.ball.pos = getStartPositionPtr(ground)
 		}
+		game.ball.prevPos = game.ball.pos
 	}
 
-	// check collision ball with emeny
-	if circleToCircle(game.ball.pos, game.ball.radius, game.enemyBall.pos, game.enemyBall.radius) {
-		game.ball.isDied = true
+	// forget smashed segments which the ball has left
+	smashed := game.smashed[:0]
+	for _, seg := range game.smashed {
+		closest := closestPointOnSegment(seg.A, seg.B, game.ball.prevPos)
+		if game.ball.prevPos.Sub(closest).Len() < game.ball.radius+wallThickness {
+			smashed = append(smashed, seg)
+		}
 	}
+	game.smashed = smashed
 
-	for _, seg := range ground {
+	// move the ball back to the first contact, so fast ball doesn't pass through segments
+	game.sweepBall()
+
+	// check collision ball with enemies and projectiles
+	for _, e := range game.enemies {
+		if circleToCircle(game.ball.pos, game.ball.radius, e.Pos, e.Radius) {
+			game.ball.isDied = true
+		}
+	}
+	for _, p := range game.projectiles {
+		if circleToCircle(game.ball.pos, game.ball.radius, p.pos, projectileRadius) {
+			game.ball.isDied = true
+		}
+	}
+
+	// segments near the ball
+	margin := collisionQueryMargin(game.ball.radius)
+	game.nearSegments = game.grid.query(game.ball.pos.X-margin, game.ball.pos.X+margin, game.nearSegments[:0])
+	for _, seg := range game.nearSegments {
 		// current position
 		closest := closestPointOnSegment(seg.A, seg.B, game.ball.pos)
 		distVec := game.ball.pos.Sub(closest)
 		dist := distVec.Len()
 
-		// // current position enemy
-		closestEnemy := closestPointOnSegment(seg.A, seg.B, game.enemyBall.pos)
-		distVecEnemy := game.enemyBall.pos.Sub(closestEnemy)
-		distEnemy := distVecEnemy.Len()
-
-		// check collision enemy with ground
-		if !seg.IsMovingWall && !seg.isBorder {
-			if distEnemy < minVec {
-				minVec = distEnemy
-
-				vec := seg.A.Sub(seg.B).Normalize()
-				vec = vec.Add(seg.A.Sub(game.enemyBall.pos).Normalize())
-				velEnemy = vec
-			}
+		// bear smashes through red segments
+		if dist < game.ball.radius+wallThickness && game.canSmash(seg) {
+			game.smash(seg, closest)
 		}
 
 		// true - collision ball with segment
-		if dist < game.ball.radius+wallThickness {
+		if dist < game.ball.radius+wallThickness && !slices.Contains(game.smashed, seg) {
 
 			// Push the wheel out of the ground
 			normal := distVec.Normalize()
@@ -1063,9 +1107,12 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 			if seg.isRed && game.getCurrentLevel().Score.getScore() > 0 {
 				game.getCurrentLevel().Score.minusScore(minusScore)
 			}
+			if seg.isRed && !seg.isBorder {
+				touchRed = true
+			}
 
-			// die if collision with moving wall
-			if seg.IsMovingWall {
+			// die if collision with moving wall or rising floor
+			if seg.IsMovingWall || seg.IsRisingFloor {
 				game.ball.isDied = true
 			}
 		}
@@ -1074,8 +1121,12 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 		if seg.savePoint != nil {
 			if circleToCircle(game.ball.pos, game.ball.radius, seg.savePoint.Position, seg.savePoint.Radius) {
 				game.getCurrentLevel().setSavePoint(seg.savePoint)
+				game.audio.PlaySound(assets.SoundSavePoint)
+				game.particles.burst(seg.savePoint.Position, 25, 6, 40, savePointColor)
+				seg.savePoint.collected = true
 				seg.savePoint = nil
 				game.getCurrentLevel().Score.plusScore(savePointScore)
+				game.getCurrentLevel().updateBestScore()
 
 				// collision with finish
 				if game.getCurrentLevel().getSavePoint().IsFinish {
@@ -1085,19 +1136,12 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 		}
 	}
 
-	// add velocity to enemy
-	if minVec != math.MaxFloat64 {
-		game.enemyBall.vel = game.enemyBall.vel.Add(velEnemy)
-	}
-
-	// respawn enemy
-	closestEnemy := closestPointOnSegment(game.borderSquare.left.A, game.borderSquare.left.B, game.enemyBall.pos)
-	distVecEnemy := game.enemyBall.pos.Sub(closestEnemy)
-	distEnemy := distVecEnemy.Len()
-
-	if distEnemy < game.enemyBall.radius || !isCircleRectangleColl(game.enemyBall.pos, game.enemyBall.radius, *game.borderSquare) {
-		game.enemyBall.pos = game.borderSquare.drawRight.B
+	// play sound only when red segment touched first time
+	if touchRed && !game.ball.onRed {
+		game.audio.PlaySound(assets.SoundRedHit)
+		game.particles.burst(game.ball.pos.Add(game.ball.vel), 15, 7, 25, yellowColor)
 	}
+	game.ball.onRed = touchRed
 
 	// add velocity to ball
 	if len(collisionSeg) > 0 {
@@ -1112,19 +1156,29 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 
 		// Handle velocity response
 		velDot := game.ball.vel.Dot(avgNormal)
-		if velDot < 0 {
 
-			// friction
-			game.ball.vel = game.ball.vel.Sub(avgNormal.Mul(velDot)).Mul(game.ball.currPhyState.friction)
+		// landing dust, the harder the more
+		if len(*gameCollSeg) == 0 && -velDot > landingMinImpact {
+			landing := game.ball.pos.Sub(avgNormal.Mul(game.ball.radius))
+			game.particles.spray(landing, avgNormal, math.Pi, int(-velDot*2), -velDot*0.5, 30, ballColor)
+			game.ball.anim.land(-velDot)
 
-			// Reflect velocity along the collision normal, friction
-			// reflected := b.vel.Sub(avgNormal.Mul(velDot))
-			// b.vel = reflected.Mul(b.currPhyState.bounceFactor)
+			// shake on hard landing
+			if -velDot > hardLandingImpact {
+				game.camera.shake((-velDot - hardLandingImpact) / hardLandingImpact)
+			}
 		}
 
-		// to avoid falling between two segments
-		if avgPenetration > 5 {
-			game.ball.vel = game.ball.vel.Add(Vector{0.5, -0.5})
+		if velDot < 0 {
+			material := game.ball.currPhyState.contactMaterial(collisionSeg)
+			if -velDot < bounceMinImpact {
+				material.Bounce = 0
+			}
+
+			// velocity along the surface slides with friction, velocity into the surface bounces back
+			normalVel := avgNormal.Mul(velDot)
+			tangentVel := game.ball.vel.Sub(normalVel)
+			game.ball.vel = tangentVel.Mul(material.Friction).Sub(normalVel.Mul(material.Bounce))
 		}
 
 		game.ball.onGround = true
@@ -1136,7 +1190,7 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 
 	// if state "A" then the ball cannot climb a high slope
 	if game.ball.currPhyState.state == phyStateA {
-		if angle > anglePhyStateA {
+		if angle > anglePhyStateA && !game.ball.form.climb {
 			game.ball.jumpVel = avgNormal.Add(game.ball.currPhyState.scrambleWall)
 		} else {
 			game.ball.jumpVel = game.ball.currPhyState.jump
@@ -1147,9 +1201,55 @@ func (game *Game) CheckCollisions(gameCollSeg *[]Segment, ground []*Segment) {
 		game.ball.jumpVel = game.ball.currPhyState.jump
 	}
 	game.ball.jumpVel = game.ball.jumpVel.Mul(game.ball.currPhyState.jumpForce)
+	game.ball.groundNormal = avgNormal
 	*gameCollSeg = collisionSeg
 }
 
+// sweepBall find the first segment on the way of the ball and stop the ball there
+func (game *Game) sweepBall() {
+	motion := game.ball.pos.Sub(game.ball.prevPos)
+	if motion.Len() == 0 {
+		return
+	}
+
+	minX := math.Min(game.ball.prevPos.X, game.ball.pos.X) - game.ball.radius
+	maxX := math.Max(game.ball.prevPos.X, game.ball.pos.X) + game.ball.radius
+	game.nearSegments = game.grid.query(minX, maxX, game.nearSegments[:0])
+
+	first := math.MaxFloat64
+	for _, seg := range game.nearSegments {
+		// the ball passes segments smashed by the bear
+		if slices.Contains(game.smashed, seg) {
+			continue
+		}
+		if t, ok := sweepCircleSegment(game.ball.prevPos, motion, game.ball.radius, seg.A, seg.B); ok {
+			if game.canSmash(seg) {
+				contact := game.ball.prevPos.Add(motion.Mul(t))
+				game.smash(seg, closestPointOnSegment(seg.A, seg.B, contact))
+				continue
+			}
+			first = math.Min(first, t)
+		}
+	}
+
+	if first <= 1 {
+		game.ball.pos = game.ball.prevPos.Add(motion.Mul(first))
+	}
+}
+
+// canSmash ball in bear form breaks the red segment
+func (game *Game) canSmash(seg *Segment) bool {
+	return seg.isRed && !seg.isBorder && game.ball.form.smashRed
+}
+
+// smash break red segment at point, it is not red anymore and the ball passes it
+func (game *Game) smash(seg *Segment, point Vector) {
+	seg.isRed = false
+	game.smashed = append(game.smashed, seg)
+	game.particles.burst(point, 20, 6, 30, yellowColor)
+	game.audio.PlaySound(assets.SoundRedHit)
+}
+
 // changeDifficulty change difficulty for score and all levels
 func (g *Game) changeDifficulty() error {
 	difficulty, err := g.score.changeDifficulty()
@@ -1182,6 +1282,7 @@ func setDifficultyVars(difficulty int) {
 		redSegmentSpawn = redSegmentSpawn_EASY
 		movWallSpeedHight = movWallSpeedHight_EASY
 		movWallSpeedSlow = movWallSpeedSlow_EASY
+		enemyAggression = enemyAggression_EASY
 	case Medium:
 		groundBuffSize = groundBuffSize_MEDIUM
 		savePointSpawn = savePointSpawn_MEDIUM
@@ -1190,6 +1291,7 @@ func setDifficultyVars(difficulty int) {
 		redSegmentSpawn = redSegmentSpawn_MEDIUM
 		movWallSpeedHight = movWallSpeedHight_MEDIUM
 		movWallSpeedSlow = movWallSpeedSlow_MEDIUM
+		enemyAggression = enemyAggression_MEDIUM
 	case Difficult:
 		groundBuffSize = groundBuffSize_DIFFICULT
 		savePointSpawn = savePointSpawn_DIFFICULT
@@ -1198,5 +1300,6 @@ func setDifficultyVars(difficulty int) {
 		redSegmentSpawn = redSegmentSpawn_DIFFICULT
 		movWallSpeedHight = movWallSpeedHight_DIFFICULT
 		movWallSpeedSlow = movWallSpeedSlow_DIFFICULT
+		enemyAggression = enemyAggression_DIFFICULT
 	}
 }
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"ball/assets"
+	"math"
+	"testing"
+)
+
+// TestSweepBallStopsAtContact ball at the velocity cap stops where it touches the segment
+// instead of moving into it or through it
+func TestSweepBallStopsAtContact(t *testing.T) {
+	radius := ballPhysicA.radius
+
+	tests := []struct {
+		name  string
+		seg   *Segment
+		start Vector
+		vel   Vector
+		want  Vector
+	}{
+		{
+			name:  "falls on thin segment",
+			seg:   &Segment{A: Vector{-200, 0}, B: Vector{200, 0}},
+			start: Vector{0, -radius - 5},
+			vel:   Vector{0, maxVelY},
+			want:  Vector{0, -radius},
+		},
+		{
+			name:  "falls on thin segment from touching distance",
+			seg:   &Segment{A: Vector{-200, 0}, B: Vector{200, 0}},
+			start: Vector{0, -radius - 0.5},
+			vel:   Vector{0, maxVelY},
+			want:  Vector{0, -radius},
+		},
+		{
+			name:  "falls on edge of thin segment",
+			seg:   &Segment{A: Vector{0, 0}, B: Vector{200, 0}},
+			start: Vector{-radius * 0.6, -radius*0.8 - 10},
+			vel:   Vector{0, maxVelY},
+			want:  Vector{-radius * 0.6, -radius * 0.8},
+		},
+		{
+			name:  "runs into moving wall",
+			seg:   &Segment{A: Vector{0, 0}, B: Vector{0, -1000}, IsMovingWall: true},
+			start: Vector{-radius - 8, -500},
+			vel:   Vector{maxVelY, 0},
+			want:  Vector{-radius, -500},
+		},
+		{
+			name:  "runs into moving wall diagonally",
+			seg:   &Segment{A: Vector{0, 0}, B: Vector{0, -1000}, IsMovingWall: true},
+			start: Vector{-radius - 10, -500},
+			vel:   Vector{maxVelY, maxVelY},
+			want:  Vector{-radius, -490},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			game := &Game{ball: NewBall(tt.start)}
+			game.grid.build([]*Segment{tt.seg})
+
+			game.ball.pos = tt.start.Add(tt.vel)
+			game.sweepBall()
+
+			if game.ball.pos.Sub(tt.want).Len() > 1e-6 {
+				t.Fatalf("pos = %v, want %v", game.ball.pos, tt.want)
+			}
+
+			// the ball touches the segment and doesn't overlap it
+			dist := game.ball.pos.Sub(closestPointOnSegment(tt.seg.A, tt.seg.B, game.ball.pos)).Len()
+			if math.Abs(dist-radius) > 1e-6 {
+				t.Errorf("distance to segment = %v, want %v", dist, radius)
+			}
+		})
+	}
+}
+
+// TestSweepBallBearSmashesRed bear passes red segment and breaks it, other forms stop on it
+func TestSweepBallBearSmashesRed(t *testing.T) {
+	radius := ballPhysicA.radius
+	start := Vector{0, -radius - 5}
+
+	for _, form := range []*Form{slimeForm, bearForm} {
+		t.Run(form.Name, func(t *testing.T) {
+			seg := &Segment{A: Vector{-200, 0}, B: Vector{200, 0}, isRed: true}
+			game := &Game{
+				ball:      NewBall(start),
+				particles: newParticles(),
+				audio:     &assets.Audio{},
+			}
+			game.ball.form = form
+			game.grid.build([]*Segment{seg})
+
+			game.ball.pos = start.Add(Vector{0, maxVelY})
+			game.sweepBall()
+
+			stopped := game.ball.pos.Sub(Vector{0, -radius}).Len() < 1e-6
+			if stopped == form.smashRed {
+				t.Errorf("ball at %v, stopped = %v", game.ball.pos, stopped)
+			}
+			if seg.isRed == form.smashRed {
+				t.Errorf("isRed = %v", seg.isRed)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package game
+
+import "math"
+
+// gridCellWidth width of one cell of segmentGrid, about a few segments of chart
+const gridCellWidth = 100.0
+
+// collisionQueryMargin distance from center of an object with radius to segments which are checked.
+// Save point is off its segment by savePointGroundOffset and the finish is the biggest one,
+// the margin also covers the fastest move of one tick.
+func collisionQueryMargin(radius float64) float64 {
+	return radius + finishRadius + savePointGroundOffset + math.Max(maxVelX, maxVelY)
+}
+
+// segmentGrid uniform grid over X, every cell keeps segments which X range overlaps the cell.
+// Ground is put into cells once per buffer swap, segments which move every tick are kept apart.
+type segmentGrid struct {
+	minX  float64
+	cells [][]*Segment
+	// dynamic moving wall, rising floor and borders, they are checked by every query
+	dynamic []*Segment
+}
+
+// cellIndex index of the cell with x, x out of grid is in the first or last cell
+func (g *segmentGrid) cellIndex(x float64) int {
+	i := int(math.Floor((x - g.minX) / gridCellWidth))
+	return max(0, min(len(g.cells)-1, i))
+}
+
+// build put static segments into cells, cells are reused
+func (g *segmentGrid) build(segments []*Segment) {
+	for i := range g.cells {
+		g.cells[i] = g.cells[i][:0]
+	}
+	if len(segments) == 0 {
+		g.cells = g.cells[:0]
+		return
+	}
+
+	minX, maxX := math.MaxFloat64, -math.MaxFloat64
+	for _, seg := range segments {
+		minX = math.Min(minX, math.Min(seg.A.X, seg.B.X))
+		maxX = math.Max(maxX, math.Max(seg.A.X, seg.B.X))
+	}
+
+	g.minX = minX
+	count := int((maxX-minX)/gridCellWidth) + 1
+	for len(g.cells) < count {
+		g.cells = append(g.cells, nil)
+	}
+	g.cells = g.cells[:count]
+
+	for _, seg := range segments {
+		first := g.cellIndex(math.Min(seg.A.X, seg.B.X))
+		last := g.cellIndex(math.Max(seg.A.X, seg.B.X))
+		for i := first; i <= last; i++ {
+			g.cells[i] = append(g.cells[i], seg)
+		}
+	}
+}
+
+// setDynamic replace segments which move every tick
+func (g *segmentGrid) setDynamic(segments []*Segment) {
+	g.dynamic = append(g.dynamic[:0], segments...)
+}
+
+// query append to out segments which X range overlaps minX..maxX, every segment once
+func (g *segmentGrid) query(minX, maxX float64, out []*Segment) []*Segment {
+	for _, seg := range g.dynamic {
+		if math.Max(seg.A.X, seg.B.X) < minX || math.Min(seg.A.X, seg.B.X) > maxX {
+			continue
+		}
+		out = append(out, seg)
+	}
+
+	if len(g.cells) == 0 {
+		return out
+	}
+
+	first := g.cellIndex(minX)
+	last := g.cellIndex(maxX)
+	for i := first; i <= last; i++ {
+		for _, seg := range g.cells[i] {
+			// segment in several cells is returned from its first cell in the range
+			if max(first, g.cellIndex(math.Min(seg.A.X, seg.B.X))) != i {
+				continue
+			}
+			if math.Max(seg.A.X, seg.B.X) < minX || math.Min(seg.A.X, seg.B.X) > maxX {
+				continue
+			}
+			out = append(out, seg)
+		}
+	}
+
+	return out
+}
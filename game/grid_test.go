@@ -0,0 +1,127 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+// benchmarkGame game with two ground buffers of buffSize segments and the ball resting in the middle
+func benchmarkGame(buffSize int) (*Game, []*Segment) {
+	segments := make([]Segment, buffSize*2)
+	for i := range segments {
+		// zigzag chart like prices
+		y0 := math.Sin(float64(i)*0.7) * 2 * multiplyChartY
+		y1 := math.Sin(float64(i+1)*0.7) * 2 * multiplyChartY
+		segments[i] = Segment{
+			A: Vector{float64(i * multiplyChartX), y0},
+			B: Vector{float64((i + 1) * multiplyChartX), y1},
+		}
+	}
+
+	g := &Game{
+		ground:    segments,
+		particles: newParticles(),
+		camera:    newCamera(),
+		movingWall: &Segment{
+			A:            Vector{-ScreenWidth, 0},
+			B:            Vector{-ScreenWidth, -1000},
+			IsMovingWall: true,
+		},
+	}
+	g.groundBuff = [2][]*Segment{
+		makeSegments(segments[:buffSize]),
+		makeSegments(segments[buffSize:]),
+	}
+
+	middle := segments[buffSize].A
+	g.ball = NewBall(Vector{middle.X, middle.Y - ballPhysicA.radius - 1})
+
+	ground, _, _, _ := g.fillGround()
+	return g, ground
+}
+
+func BenchmarkCheckCollisions(b *testing.B) {
+	for _, buffSize := range []int{groundBuffSize_DIFFICULT, groundBuffSize_EASY, 500, 5000} {
+		g, ground := benchmarkGame(buffSize)
+		start := g.ball.pos
+
+		// linear all segments are in the dynamic list which every query scans, like before the grid
+		b.Run(fmt.Sprintf("linear/%d", buffSize), func(b *testing.B) {
+			g.grid = segmentGrid{}
+			g.grid.setDynamic(ground)
+
+			for i := 0; i < b.N; i++ {
+				g.ball.pos, g.ball.prevPos, g.ball.vel = start, start, Vector{}
+				g.CheckCollisions(&g.collisionSeg, ground)
+			}
+		})
+
+		// grid ground is put into cells once, moving segments every tick like in gameUpdate
+		b.Run(fmt.Sprintf("grid/%d", buffSize), func(b *testing.B) {
+			g.grid = segmentGrid{}
+			g.buildGrid()
+
+			for i := 0; i < b.N; i++ {
+				g.grid.setDynamic(ground[buffSize*2:])
+				g.ball.pos, g.ball.prevPos, g.ball.vel = start, start, Vector{}
+				g.CheckCollisions(&g.collisionSeg, ground)
+			}
+		})
+	}
+}
+
+func TestSegmentGridQuery(t *testing.T) {
+	long := &Segment{A: Vector{0, 0}, B: Vector{450, 0}}
+	short := &Segment{A: Vector{500, 0}, B: Vector{510, 0}}
+	far := &Segment{A: Vector{2000, 0}, B: Vector{2010, 0}}
+	wall := &Segment{A: Vector{300, 0}, B: Vector{300, -500}, IsMovingWall: true}
+
+	var grid segmentGrid
+	grid.build([]*Segment{long, short, far})
+	grid.setDynamic([]*Segment{wall})
+
+	got := grid.query(250, 520, nil)
+	want := map[*Segment]bool{long: true, short: true, wall: true}
+	if len(got) != len(want) {
+		t.Fatalf("query returned %d segments, want %d", len(got), len(want))
+	}
+	for _, seg := range got {
+		if !want[seg] {
+			t.Errorf("unexpected segment %v", *seg)
+		}
+	}
+}
+
+// TestSegmentGridQueryCellEdge segment in the next cell is found when the biggest ball touches its finish
+func TestSegmentGridQueryCellEdge(t *testing.T) {
+	radius := ballPhysicB.radius
+	first := &Segment{A: Vector{0, 0}, B: Vector{gridCellWidth * 2, 0}}
+	last := &Segment{A: Vector{gridCellWidth * 2, 0}, B: Vector{gridCellWidth*2 + multiplyChartX, 0}}
+	// steep segment moves its save point off the segment to the left
+	last.savePoint = &SavePoint{
+		Position: Vector{last.A.X - savePointGroundOffset, -100},
+		Radius:   finishRadius,
+		IsFinish: true,
+	}
+
+	var grid segmentGrid
+	grid.build([]*Segment{first, last})
+
+	pos := Vector{last.savePoint.Position.X - radius - finishRadius + 1, -100}
+	if !circleToCircle(pos, radius, last.savePoint.Position, last.savePoint.Radius) {
+		t.Fatal("ball doesn't touch the finish")
+	}
+	if grid.cellIndex(pos.X) == grid.cellIndex(last.A.X) {
+		t.Fatal("ball is in the cell of the segment")
+	}
+
+	margin := collisionQueryMargin(radius)
+	found := false
+	for _, seg := range grid.query(pos.X-margin, pos.X+margin, nil) {
+		found = found || seg == last
+	}
+	if !found {
+		t.Errorf("segment with finish at %v is not found for ball at %v", last.savePoint.Position, pos)
+	}
+}
@@ -21,13 +21,26 @@ type Level struct {
 
 	CurrentDifficulty int
 	LevelEntities     map[int]*LevelEntities `json:"levelEntities,omitempty"`
+
+	// Background parallax layers, default layers if empty
+	Background []BackgroundLayer `json:"background,omitempty"`
+	// Physics preset name, preset of difficulty if empty
+	Physics string `json:"physics,omitempty"`
+	// Wall moving wall of the level, wall of difficulty if empty
+	Wall *WallDefinition `json:"wall,omitempty"`
+	// RisingFloor floor rises from the bottom when the player lingers
+	RisingFloor bool `json:"risingFloor,omitempty"`
 }
 
 type LevelEntities struct {
-	Finished     bool       `json:"finished"`
-	SavePoint    *SavePoint `json:"savePoint,omitempty"`
-	MovingWall   *Segment   `json:"movingWall,omitempty"`
-	EnemyBallPos *Vector    `json:"enemyBallPos,omitempty"`
+	Finished    bool       `json:"finished"`
+	SavePoint   *SavePoint `json:"savePoint,omitempty"`
+	MovingWall  *Segment   `json:"movingWall,omitempty"`
+	RisingFloor *Segment   `json:"risingFloor,omitempty"`
+	// EnemyBallPos position of the chaser in old levels, Enemies are used now
+	EnemyBallPos *Vector  `json:"enemyBallPos,omitempty"`
+	Enemies      []*Enemy `json:"enemies,omitempty"`
+	BestScore    int      `json:"bestScore,omitempty"`
 }
 
 func NewLevelEntities() map[int]*LevelEntities {
@@ -59,13 +72,41 @@ func (l *Level) getMovingWall() *Segment {
 	return l.LevelEntities[l.CurrentDifficulty].MovingWall
 }
 
-func (l *Level) setEnemyBallPos(enemyBallPos *Vector) {
-	l.LevelEntities[l.CurrentDifficulty].EnemyBallPos = enemyBallPos
+func (l *Level) setRisingFloor(risingFloor *Segment) {
+	l.LevelEntities[l.CurrentDifficulty].RisingFloor = risingFloor
+}
+
+func (l *Level) getRisingFloor() *Segment {
+	return l.LevelEntities[l.CurrentDifficulty].RisingFloor
+}
+
+// setEnemies save copy of enemies, the game keeps changing its enemies
+func (l *Level) setEnemies(enemies []*Enemy) {
+	var saved []*Enemy
+	for _, e := range enemies {
+		enemy := *e
+		saved = append(saved, &enemy)
+	}
+
+	l.LevelEntities[l.CurrentDifficulty].Enemies = saved
+	l.LevelEntities[l.CurrentDifficulty].EnemyBallPos = nil
+}
+
+// getEnemies copy of saved enemies
+func (l *Level) getEnemies() []*Enemy {
+	var enemies []*Enemy
+	for _, e := range l.LevelEntities[l.CurrentDifficulty].Enemies {
+		enemy := *e
+		enemies = append(enemies, &enemy)
+	}
+	return enemies
 }
 
+// getEnemyBallPos position of the chaser saved by old version
 func (l *Level) getEnemyBallPos() *Vector {
 	return l.LevelEntities[l.CurrentDifficulty].EnemyBallPos
 }
+
 func (l *Level) getFinished() bool {
 	return l.LevelEntities[l.CurrentDifficulty].Finished
 }
@@ -74,9 +115,26 @@ func (l *Level) setFinished(finished bool) {
 	l.LevelEntities[l.CurrentDifficulty].Finished = finished
 }
 
+func (l *Level) getBestScore(difficulty int) int {
+	if l.LevelEntities[difficulty] == nil {
+		return 0
+	}
+	return l.LevelEntities[difficulty].BestScore
+}
+
+// updateBestScore remember current score if it is the best one
+func (l *Level) updateBestScore() {
+	if l.Score.getScore() > l.LevelEntities[l.CurrentDifficulty].BestScore {
+		l.LevelEntities[l.CurrentDifficulty].BestScore = l.Score.getScore()
+	}
+}
+
 func (l *Level) resetLevel() {
 	l.Score.setScore(defaultScore)
-	l.LevelEntities[l.CurrentDifficulty] = &LevelEntities{}
+	// keep best score after reset
+	l.LevelEntities[l.CurrentDifficulty] = &LevelEntities{
+		BestScore: l.getBestScore(l.CurrentDifficulty),
+	}
 }
 
 // saveLevel marshals level to json and save it in file
@@ -0,0 +1,173 @@
+package game
+
+import (
+	"ball/assets"
+	"fmt"
+	"image/color"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+const (
+	thumbnailX      = 200
+	thumbnailY      = 110
+	thumbnailWidth  = 900
+	thumbnailHeight = 300
+)
+
+// LevelDetail preview of the level before start
+type LevelDetail struct {
+	thumbnail *ChartThumbnail
+}
+
+// openLevelDetail read level chart and show level detail
+func (g *Game) openLevelDetail(lvlIdx int) error {
+	level := g.levels[lvlIdx]
+
+	points, err := readLevelCSV(filepath.Join(GameFilesDir, level.ChartFile))
+	if err != nil {
+		return fmt.Errorf("failed to read level data: %w", err)
+	}
+	if len(points) < 2 {
+		return fmt.Errorf("too small points for level")
+	}
+
+	if g.levelDetail != nil {
+		g.levelDetail.thumbnail.deallocate()
+	}
+
+	g.levelDetail = &LevelDetail{
+		thumbnail: newChartThumbnail(points, thumbnailWidth, thumbnailHeight, 2, groundColor),
+	}
+	g.currentLevel = lvlIdx
+	g.currentState = StateLevelDetail
+
+	return nil
+}
+
+// playLevel start current level if it is not finished
+func (g *Game) playLevel() {
+	if !g.getCurrentLevel().getFinished() {
+		g.currentState = StateLoadingLevel
+	}
+}
+
+// updateLevelDetail process keyboard in level detail
+func (g *Game) updateLevelDetail() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.currentState = StateLevelSelect
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		g.playLevel()
+	}
+}
+
+func (g *Game) drawLevelDetail(screen *ebiten.Image) {
+	g.drawMenuBackground(screen)
+	level := g.getCurrentLevel()
+	left := g.uiLeft() + thumbnailX
+
+	// Draw title
+	title := fmt.Sprintf("%s (%s)", level.Name, level.Ticker)
+	w, h := text.Measure(title, assets.ScoreFaceBig, 0)
+	options := &text.DrawOptions{}
+	options.GeoM.Translate(g.uiCenterX()-w/2, 50-h/2)
+	options.ColorScale.ScaleWithColor(color.White)
+	uiDrawText(screen, title, assets.ScoreFaceBig, options)
+
+	// Draw thumbnail
+	thumbOptions := &ebiten.DrawImageOptions{}
+	thumbOptions.GeoM.Translate(left, thumbnailY)
+	uiDrawImage(screen, g.levelDetail.thumbnail.image, thumbOptions)
+
+	// Draw save points of all difficulties, the current one is bigger
+	for difficulty := Easy; difficulty <= Difficult; difficulty++ {
+		entities := level.LevelEntities[difficulty]
+		if entities == nil || entities.SavePoint == nil {
+			continue
+		}
+
+		radius := float32(6)
+		if difficulty == level.CurrentDifficulty {
+			radius = 10
+		}
+
+		pos := g.levelDetail.thumbnail.toThumbnail(entities.SavePoint.Position)
+		uiFillCircle(screen, float32(left+pos.X), float32(thumbnailY+pos.Y), radius, getDifficultColor(difficulty))
+	}
+
+	// Draw progress and scores of all difficulties
+	for difficulty := Easy; difficulty <= Difficult; difficulty++ {
+		y := 440 + float64(difficulty)*70
+
+		nameBtn := Button{
+			X: left, Y: y, Width: 250, Height: 60,
+			Text:       getDifficultName(difficulty),
+			Color:      getDifficultColor(difficulty),
+			HoverColor: getDifficultColor(difficulty),
+		}
+		drawButtonText(screen, &nameBtn)
+
+		progress := calculateDifficultyProgress(*level, difficulty)
+		progressBtn := Button{
+			X: left + 260, Y: y, Width: 640, Height: 60,
+			Text: fmt.Sprintf("%d%%  %d$  BEST %d$",
+				progress, level.Score.Difficulty[difficulty], level.getBestScore(difficulty)),
+			Color:      groundColor,
+			HoverColor: groundColor,
+		}
+		drawButton(screen, &progressBtn)
+		uiFillRect(screen,
+			float32(progressBtn.X),
+			float32(progressBtn.Y),
+			float32(progressBtn.Width*float64(progress)/100),
+			float32(progressBtn.Height),
+			ballColor)
+		drawText(screen, &progressBtn)
+
+		// mark current difficulty
+		if difficulty == level.CurrentDifficulty {
+			uiFillRect(screen, float32(left-20), float32(y), 10, 60, color.White)
+		}
+	}
+
+	// Draw play and reset buttons
+	playCol, playColHover := ballColor, ballColorBig
+	resetText := fmt.Sprintf("RESET %d$", level.Score.getScore())
+	if level.getFinished() {
+		playCol, playColHover = groundColor, groundColorHover
+		resetText = fmt.Sprintf("RESET %d(x%d)$", level.Score.getScore(), finishLevelSell)
+	}
+
+	buttons := []Button{
+		{
+			X: left, Y: 670, Width: 445, Height: 60,
+			Text:       "PLAY",
+			Color:      playCol,
+			HoverColor: playColHover,
+			Action:     g.playLevel,
+		},
+		{
+			X: left + 455, Y: 670, Width: 445, Height: 60,
+			Text:       resetText,
+			Color:      wallColor,
+			HoverColor: wallColorHover,
+			Action: func() {
+				g.drawError = resetLevel(level, g)
+			},
+		},
+	}
+
+	for i, btn := range buttons {
+		drawButtonText(screen, &buttons[i])
+		if btn.IsClicked() {
+			btn.Action()
+		}
+	}
+
+	// Draw return button
+	g.drawReturnButton(screen, StateLevelSelect)
+}
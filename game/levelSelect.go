@@ -0,0 +1,278 @@
+package game
+
+import (
+	"ball/assets"
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// level list sort modes
+const (
+	sortByNumber = iota
+	sortByName
+	sortByProgress
+	sortByScore
+)
+
+const (
+	// levelListTop Y of the first row
+	levelListTop = 150
+	// levelRowHeight distance between rows
+	levelRowHeight = 80
+	// levelListBottom space under the list for the return button
+	levelListBottom = 90
+	// levelFilterMaxLen max length of the ticker filter
+	levelFilterMaxLen = 8
+)
+
+// LevelSelect state of the level select list
+type LevelSelect struct {
+	// offset index of the first visible row
+	offset   int
+	sortMode int
+	// filter part of the ticker
+	filter string
+}
+
+func getSortName(sortMode int) string {
+	switch sortMode {
+	case sortByNumber:
+		return "NUMBER"
+	case sortByName:
+		return "NAME"
+	case sortByProgress:
+		return "PROGRESS"
+	case sortByScore:
+		return "SCORE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// sortLevels sort levels by Level.Number, levels with the same number by ticker
+func sortLevels(levels []*Level) {
+	sort.SliceStable(levels, func(i, j int) bool {
+		if levels[i].Number != levels[j].Number {
+			return levels[i].Number < levels[j].Number
+		}
+		return levels[i].Ticker < levels[j].Ticker
+	})
+}
+
+// changeSort switch to the next sort mode
+func (l *LevelSelect) changeSort() {
+	l.sortMode = (l.sortMode + 1) % (sortByScore + 1)
+	l.offset = 0
+}
+
+// levelIndexes return indexes of g.levels filtered and sorted for the list
+func (g *Game) levelIndexes() []int {
+	indexes := make([]int, 0, len(g.levels))
+	for i, level := range g.levels {
+		if strings.Contains(strings.ToUpper(level.Ticker), g.levelSelect.filter) {
+			indexes = append(indexes, i)
+		}
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := g.levels[indexes[i]], g.levels[indexes[j]]
+		switch g.levelSelect.sortMode {
+		case sortByName:
+			return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+		case sortByProgress:
+			return calculateLevelProgress(*a) > calculateLevelProgress(*b)
+		case sortByScore:
+			return a.Score.getScore() > b.Score.getScore()
+		default:
+			// g.levels is already sorted by number
+			return false
+		}
+	})
+
+	return indexes
+}
+
+// levelListRows how many rows fit above the return button
+func (g *Game) levelListRows() int {
+	return max(1, int(g.screenHeight-levelListTop-levelListBottom+levelRowHeight-60)/levelRowHeight)
+}
+
+// scrollLevels move list by rows and keep it inside bounds
+func (g *Game) scrollLevels(rows int) {
+	maxOffset := len(g.levelIndexes()) - g.levelListRows()
+	if maxOffset < 0 {
+		maxOffset = 0
+	}
+
+	g.levelSelect.offset += rows
+	if g.levelSelect.offset > maxOffset {
+		g.levelSelect.offset = maxOffset
+	}
+	if g.levelSelect.offset < 0 {
+		g.levelSelect.offset = 0
+	}
+}
+
+// updateLevelSelect process keyboard and mouse wheel in level select
+func (g *Game) updateLevelSelect() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		// clean filter first
+		if g.levelSelect.filter != "" {
+			g.levelSelect.filter = ""
+			g.scrollLevels(0)
+		} else {
+			g.currentState = StateMenu
+		}
+		return
+	}
+
+	// filter by ticker
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if len(g.levelSelect.filter) < levelFilterMaxLen && r < 128 && r > ' ' {
+			g.levelSelect.filter += strings.ToUpper(string(r))
+			g.levelSelect.offset = 0
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyBackspace) && g.levelSelect.filter != "" {
+		g.levelSelect.filter = g.levelSelect.filter[:len(g.levelSelect.filter)-1]
+	}
+
+	// paging
+	_, wheelY := ebiten.Wheel()
+	switch {
+	case wheelY > 0 || inpututil.IsKeyJustPressed(ebiten.KeyUp):
+		g.scrollLevels(-1)
+	case wheelY < 0 || inpututil.IsKeyJustPressed(ebiten.KeyDown):
+		g.scrollLevels(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyPageUp):
+		g.scrollLevels(-g.levelListRows())
+	case inpututil.IsKeyJustPressed(ebiten.KeyPageDown):
+		g.scrollLevels(g.levelListRows())
+	case inpututil.IsKeyJustPressed(ebiten.KeyHome):
+		g.scrollLevels(-len(g.levels))
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnd):
+		g.scrollLevels(len(g.levels))
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		g.levelSelect.changeSort()
+	}
+}
+
+func (g *Game) drawLevelSelect(screen *ebiten.Image) {
+	// Background color
+	g.drawMenuBackground(screen)
+	left := g.uiLeft()
+	rows := g.levelListRows()
+
+	// Draw title
+	title := "SELECT LEVEL"
+	w, h := text.Measure(title, assets.ScoreFaceBig, 0)
+	options := &text.DrawOptions{}
+	options.GeoM.Translate(g.uiCenterX()-w/2, 50-h/2)
+	options.ColorScale.ScaleWithColor(color.White)
+	uiDrawText(screen, title, assets.ScoreFaceBig, options)
+
+	options2 := &text.DrawOptions{}
+	options2.GeoM.Translate(left+200, 70)
+	options2.ColorScale.ScaleWithColor(color.White)
+
+	// Draw score
+	score := fmt.Sprintf("Score: %s$", strconv.Itoa(g.score.getScore()))
+	uiDrawText(screen, score, assets.ScoreFace, options2)
+
+	// Draw filter box
+	filterBtn := Button{
+		X: left + 560, Y: 80, Width: 200, Height: 50,
+		Text:       "FIND: " + g.levelSelect.filter,
+		Color:      groundColor,
+		HoverColor: groundColorHover,
+	}
+	drawButtonText(screen, &filterBtn)
+
+	// Draw sort button
+	sortBtn := Button{
+		X: left + 770, Y: 80, Width: 190, Height: 50,
+		Text:       getSortName(g.levelSelect.sortMode),
+		Color:      groundColor,
+		HoverColor: groundColorHover,
+		Action:     g.levelSelect.changeSort,
+	}
+	drawButtonText(screen, &sortBtn)
+	if sortBtn.IsClicked() {
+		sortBtn.Action()
+	}
+
+	// Draw levels
+	indexes := g.levelIndexes()
+	start := min(g.levelSelect.offset, len(indexes))
+	end := min(start+rows, len(indexes))
+	for row, i := range indexes[start:end] {
+		level := g.levels[i]
+		y := levelListTop + float64(row)*levelRowHeight
+
+		levelButton := Button{
+			X: left + 200, Y: y, Width: 400, Height: 60,
+			Text:       level.Name,
+			Color:      groundColor,
+			HoverColor: groundColorHover,
+			Action: func() {
+				g.drawError = g.openLevelDetail(i)
+			},
+		}
+
+		sellLevelBtnCol := groundColor
+		sellLevelBtnColHover := groundColorHover
+		if level.Score.getScore() > 0 {
+			sellLevelBtnCol = ballColor
+			sellLevelBtnColHover = ballColorBig
+		}
+
+		text := fmt.Sprintf("%d$", level.Score.getScore())
+
+		if level.getFinished() {
+			text = fmt.Sprintf("%d(x%d)$", level.Score.getScore(), finishLevelSell)
+		}
+
+		sellLevel := Button{
+			X: levelButton.X + levelButton.Width + 10, Y: y, Width: 350, Height: 60,
+			Text:       text,
+			Color:      sellLevelBtnCol,
+			HoverColor: sellLevelBtnColHover,
+			Action: func() {
+				err := resetLevel(level, g)
+				g.drawError = err
+			},
+		}
+
+		// Draw level button
+		drawProgressButton(screen, &levelButton, level)
+		if levelButton.IsClicked() {
+			levelButton.Action()
+		}
+
+		// Draw sell level
+		drawButtonText(screen, &sellLevel)
+		if sellLevel.IsClicked() {
+			sellLevel.Action()
+		}
+	}
+
+	// Draw scroll bar
+	if len(indexes) > rows {
+		barHeight := float64(rows*levelRowHeight - (levelRowHeight - 60))
+		thumbHeight := barHeight * float64(rows) / float64(len(indexes))
+		thumbY := levelListTop + barHeight*float64(g.levelSelect.offset)/float64(len(indexes))
+
+		uiFillRect(screen, float32(left+970), levelListTop, 10, float32(barHeight), groundColor)
+		uiFillRect(screen, float32(left+970), float32(thumbY), 10, float32(thumbHeight), groundColorHover)
+	}
+
+	// Draw return button
+	g.drawReturnButton(screen, StateMenu)
+}
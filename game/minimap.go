@@ -0,0 +1,73 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// minimapRight distance from the right edge of screen
+	minimapRight  = 20
+	minimapY      = 10
+	minimapWidth  = 880
+	minimapHeight = 60
+)
+
+var (
+	minimapBackground = color.RGBA{0, 20, 20, 200}
+	minimapCollected  = color.RGBA{60, 90, 60, 255}
+)
+
+// drawMinimap draw the whole chart with ball, enemy, moving wall and save points
+func (g *Game) drawMinimap(screen *ebiten.Image) {
+	if g.minimap == nil {
+		return
+	}
+
+	// anchored to the right edge
+	minimapX := g.screenWidth - minimapWidth - minimapRight
+
+	options := &ebiten.DrawImageOptions{}
+	options.GeoM.Translate(minimapX, minimapY)
+	uiDrawImage(screen, g.minimap.image, options)
+
+	// toMinimap convert level position to screen position on minimap
+	toMinimap := func(pos Vector) (float32, float32) {
+		p := g.minimap.toThumbnail(pos)
+		return float32(minimapX + p.X), float32(minimapY + p.Y)
+	}
+
+	// visible part of the level
+	left, _ := toMinimap(Vector{g.camera.X, 0})
+	right, _ := toMinimap(Vector{g.camera.X + g.camera.viewWidth(), 0})
+	uiStrokeRect(screen, left, minimapY, right-left, minimapHeight, 1, groundColorHover)
+
+	// save points, finish is bigger
+	for _, savePoint := range g.savePoints {
+		x, y := toMinimap(savePoint.Position)
+		switch {
+		case savePoint.IsFinish:
+			uiFillCircle(screen, x, y, 5, savePointColor)
+			uiFillCircle(screen, x, y, 3, color.Black)
+		case savePoint.collected:
+			uiFillCircle(screen, x, y, 2, minimapCollected)
+		default:
+			uiFillCircle(screen, x, y, 2, savePointColor)
+		}
+	}
+
+	// moving wall
+	wallX, _ := toMinimap(g.movingWall.A)
+	uiStrokeLine(screen, wallX, minimapY, wallX, minimapY+minimapHeight, 2, wallColor)
+
+	// enemies
+	for _, e := range g.enemies {
+		x, y := toMinimap(e.Pos)
+		uiFillCircle(screen, x, y, 3, wallColor)
+	}
+
+	// ball
+	x, y := toMinimap(g.ball.pos)
+	uiFillCircle(screen, x, y, 4, ballColor)
+}
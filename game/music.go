@@ -0,0 +1,65 @@
+package game
+
+import (
+	"ball/assets"
+	"math"
+)
+
+const (
+	// musicSlopeRange distance from the ball to segments which make slope
+	musicSlopeRange = 300.0
+	// musicSlopeMax slope with max intensity
+	musicSlopeMax = 20.0
+	// musicIntensityLerp how fast music follows the game
+	musicIntensityLerp = 0.05
+)
+
+// updateMusic crossfade music depending on the game state
+func (g *Game) updateMusic() {
+	switch g.currentState {
+	case StateMenu, StateLevelSelect, StateLevelDetail:
+		g.audio.PlayMusic(assets.MusicMenu)
+	case StateLoadingLevel, StatePlaying, StatePaused:
+		g.audio.PlayMusic(assets.MusicPlay)
+	}
+
+	// music is calm in pause
+	target := 0.0
+	if g.currentState == StatePlaying && g.ball != nil {
+		target = g.musicTension()
+	}
+	g.musicIntensity += (target - g.musicIntensity) * musicIntensityLerp
+	g.audio.SetIntensity(g.musicIntensity)
+
+	g.audio.Update()
+}
+
+// musicTension return 0..1, steep ground near the ball or close moving wall make it higher
+func (g *Game) musicTension() float64 {
+	// average slope of the ground near the ball
+	slopeSum := 0.0
+	count := 0
+	for _, buff := range g.groundBuff {
+		for _, seg := range buff {
+			if math.Abs(seg.AvrX()-g.ball.pos.X) > musicSlopeRange || seg.B.X == seg.A.X {
+				continue
+			}
+			slopeSum += math.Abs((seg.B.Y - seg.A.Y) / (seg.B.X - seg.A.X))
+			count++
+		}
+	}
+
+	slope := 0.0
+	if count > 0 {
+		slope = math.Min(1, slopeSum/float64(count)/musicSlopeMax)
+	}
+
+	// moving wall is tense within screen width
+	wall := 0.0
+	if g.movingWall != nil {
+		distance := math.Abs(g.ball.pos.X - g.movingWall.A.X)
+		wall = 1 - math.Min(1, distance/ScreenWidth)
+	}
+
+	return math.Max(slope, wall)
+}
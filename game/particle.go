@@ -0,0 +1,112 @@
+package game
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// particlesPoolSize max alive particles, the oldest are replaced
+	particlesPoolSize = 512
+	particleGravity   = 0.2
+	particleRadius    = 6.0
+	// landingMinImpact min velocity into ground which raises dust
+	landingMinImpact = 3.0
+)
+
+// Particle is a single dot with lifetime
+type Particle struct {
+	pos     Vector
+	vel     Vector
+	life    int
+	maxLife int
+	radius  float64
+	color   color.RGBA
+}
+
+// Particles pool of particles
+type Particles struct {
+	pool []Particle
+	// next slot in pool, pool is used as a ring
+	next int
+}
+
+func newParticles() *Particles {
+	return &Particles{
+		pool: make([]Particle, particlesPoolSize),
+	}
+}
+
+// emit add one particle
+func (p *Particles) emit(pos, vel Vector, life int, radius float64, c color.RGBA) {
+	p.pool[p.next] = Particle{
+		pos:     pos,
+		vel:     vel,
+		life:    life,
+		maxLife: life,
+		radius:  radius,
+		color:   c,
+	}
+	p.next = (p.next + 1) % len(p.pool)
+}
+
+// spray emit count particles from pos along direction with spread in radians
+func (p *Particles) spray(pos, direction Vector, spread float64, count int, speed float64, life int, c color.RGBA) {
+	angle := math.Atan2(direction.Y, direction.X)
+	for i := 0; i < count; i++ {
+		a := angle + (rand.Float64()-0.5)*spread
+		s := speed * (0.5 + rand.Float64()*0.5)
+		vel := Vector{math.Cos(a) * s, math.Sin(a) * s}
+		radius := particleRadius * (0.5 + rand.Float64()*0.5)
+
+		p.emit(pos, vel, life/2+rand.Intn(life/2+1), radius, c)
+	}
+}
+
+// burst emit count particles from pos in all directions
+func (p *Particles) burst(pos Vector, count int, speed float64, life int, c color.RGBA) {
+	p.spray(pos, Vector{0, -1}, 2*math.Pi, count, speed, life, c)
+}
+
+func (p *Particles) Update() {
+	for i := range p.pool {
+		pr := &p.pool[i]
+		if pr.life <= 0 {
+			continue
+		}
+
+		pr.life--
+		pr.vel.Y += particleGravity
+		pr.vel = pr.vel.Mul(0.98)
+		pr.pos = pr.pos.Add(pr.vel)
+	}
+}
+
+func (p *Particles) Draw(screen *ebiten.Image, camera *Camera) {
+	for _, pr := range p.pool {
+		if pr.life <= 0 {
+			continue
+		}
+
+		// fade out, color is premultiplied by alpha
+		fade := float64(pr.life) / float64(pr.maxLife)
+		c := color.RGBA{
+			R: uint8(float64(pr.color.R) * fade),
+			G: uint8(float64(pr.color.G) * fade),
+			B: uint8(float64(pr.color.B) * fade),
+			A: uint8(float64(pr.color.A) * fade),
+		}
+
+		camera.drawCircle(screen, pr.pos, pr.radius*(0.5+fade*0.5), c)
+	}
+}
+
+// clear kill all particles
+func (p *Particles) clear() {
+	for i := range p.pool {
+		p.pool[i].life = 0
+	}
+}
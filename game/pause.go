@@ -0,0 +1,129 @@
+package game
+
+import (
+	"ball/assets"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+var pauseOverlayColor = color.RGBA{0, 0, 0, 160}
+
+// updatePaused process keyboard in pause, the simulation is frozen
+func (g *Game) updatePaused() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.resume()
+	}
+}
+
+// resume continue playing
+func (g *Game) resume() {
+	g.currentState = StatePlaying
+}
+
+// quitToLevelSelect save moving wall, rising floor and enemies and return to level select
+func (g *Game) quitToLevelSelect() error {
+	g.getCurrentLevel().setMovingWall(g.movingWall)
+	g.getCurrentLevel().setRisingFloor(g.risingFloor)
+	g.getCurrentLevel().setEnemies(g.enemies)
+
+	return returnToSelectLevel(g)
+}
+
+// restartFromSavePoint load level again from the last save point
+func (g *Game) restartFromSavePoint() error {
+	// moving wall, rising floor and enemies start again behind the save point
+	g.getCurrentLevel().setMovingWall(nil)
+	g.getCurrentLevel().setRisingFloor(nil)
+	g.getCurrentLevel().setEnemies(nil)
+
+	err := returnToSelectLevel(g)
+	if err != nil {
+		return err
+	}
+
+	g.currentState = StateLoadingLevel
+	return nil
+}
+
+// restartLevel reset level score and progress and load level from the start
+func (g *Game) restartLevel() error {
+	// level score goes to the total score like reset in level select
+	err := resetLevel(g.getCurrentLevel(), g)
+	if err != nil {
+		return err
+	}
+
+	err = returnToSelectLevel(g)
+	if err != nil {
+		return err
+	}
+
+	g.currentState = StateLoadingLevel
+	return nil
+}
+
+func (g *Game) drawPaused(screen *ebiten.Image) {
+	// frozen game under overlay
+	g.drawPlaying(screen)
+	uiFillRect(screen, 0, 0, float32(g.screenWidth), float32(g.screenHeight), pauseOverlayColor)
+
+	// Draw title
+	title := "PAUSE"
+	w, h := text.Measure(title, assets.ScoreFaceBig, 0)
+	options := &text.DrawOptions{}
+	options.GeoM.Translate(g.uiCenterX()-w/2, 130-h/2)
+	options.ColorScale.ScaleWithColor(color.White)
+	uiDrawText(screen, title, assets.ScoreFaceBig, options)
+
+	buttons := []Button{
+		{
+			Text:       "RESUME",
+			Color:      ballColor,
+			HoverColor: ballColorBig,
+			Action:     g.resume,
+		},
+		{
+			Text:       "RESTART FROM SAVE",
+			Color:      groundColor,
+			HoverColor: groundColorHover,
+			Action:     func() { g.drawError = g.restartFromSavePoint() },
+		},
+		{
+			Text:       "RESTART LEVEL",
+			Color:      yellowColor,
+			HoverColor: yellowColorHover,
+			Action:     func() { g.drawError = g.restartLevel() },
+		},
+		{
+			Text:       "SETTINGS",
+			Color:      groundColor,
+			HoverColor: groundColorHover,
+			Action:     g.openSettings,
+		},
+		{
+			Text:       "QUIT",
+			Color:      wallColor,
+			HoverColor: wallColorHover,
+			Action:     func() { g.drawError = g.quitToLevelSelect() },
+		},
+	}
+
+	for i := range buttons {
+		buttons[i].X = g.uiCenterX() - 200
+		buttons[i].Y = 200 + float64(i)*80
+		buttons[i].Width = 400
+		buttons[i].Height = 60
+	}
+
+	for i, btn := range buttons {
+		drawButtonText(screen, &buttons[i])
+		if btn.IsClicked() {
+			btn.Action()
+			// state has changed, other buttons are not valid
+			return
+		}
+	}
+}
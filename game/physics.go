@@ -0,0 +1,272 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// physicsFileName presets of ball physics, it is json so designers can edit it
+	physicsFileName = "physics.json"
+	// defaultPhysicsPreset preset which is used if difficulty or level doesn't set one
+	defaultPhysicsPreset = "default"
+	// physicsReloadTicks how often physics file is checked for changes
+	physicsReloadTicks = 60
+)
+
+// PhysicValues values of BallPhysic in physics file
+type PhysicValues struct {
+	Gravity      float64 `json:"gravity"`
+	Friction     float64 `json:"friction"`
+	Radius       float64 `json:"radius"`
+	SpeedRun     float64 `json:"speedRun"`
+	Jump         Vector  `json:"jump"`
+	JumpForce    float64 `json:"jumpForce"`
+	ScrambleWall Vector  `json:"scrambleWall"`
+	// BounceFactor 0 = no bounce, 1 = perfect bounce
+	BounceFactor float64 `json:"bounceFactor"`
+	// CoyoteTicks, JumpBufferTicks forgiveness of jump timing in ticks, 0 = off.
+	// Values missing in the file are taken from default physics, see PhysicsPreset.UnmarshalJSON
+	CoyoteTicks     int `json:"coyoteTicks"`
+	JumpBufferTicks int `json:"jumpBufferTicks"`
+	// JumpCut part of upward velocity kept when Space is released, 0 or 1 = fixed jump height
+	JumpCut float64 `json:"jumpCut"`
+	// Materials per surface: ground, red, border
+	Materials map[string]Material `json:"materials,omitempty"`
+}
+
+// PhysicsPreset physics of both ball states
+type PhysicsPreset struct {
+	Name string       `json:"name"`
+	A    PhysicValues `json:"a"`
+	B    PhysicValues `json:"b"`
+}
+
+// UnmarshalJSON read preset over default physics,
+// so files written before a value was added get the default value instead of 0.
+// Materials of the file are merged into default materials.
+func (p *PhysicsPreset) UnmarshalJSON(data []byte) error {
+	// preset without methods, so Unmarshal doesn't call UnmarshalJSON again
+	type preset PhysicsPreset
+	loaded := preset{
+		A: physicValues(defaultBallPhysicA),
+		B: physicValues(defaultBallPhysicB),
+	}
+
+	err := json.Unmarshal(data, &loaded)
+	if err != nil {
+		return err
+	}
+
+	*p = PhysicsPreset(loaded)
+	return nil
+}
+
+// Physics physics file
+type Physics struct {
+	Presets []PhysicsPreset `json:"presets"`
+	// Difficulty preset name for Easy, Medium and Difficult
+	Difficulty []string `json:"difficulty"`
+
+	// modTime modification time of loaded file, ticks ticks since the last check
+	modTime time.Time
+	ticks   int
+}
+
+func physicValues(p BallPhysic) PhysicValues {
+	return PhysicValues{
+		Gravity:         p.gravity,
+		Friction:        p.friction,
+		Radius:          p.radius,
+		SpeedRun:        p.speedRun,
+		Jump:            p.jump,
+		JumpForce:       p.jumpForce,
+		ScrambleWall:    p.scrambleWall,
+		BounceFactor:    p.bounceFactor,
+		CoyoteTicks:     p.coyoteTicks,
+		JumpBufferTicks: p.jumpBufferTicks,
+		JumpCut:         p.jumpCut,
+		Materials:       maps.Clone(p.materials),
+	}
+}
+
+// ballPhysic BallPhysic of state from values
+func (v PhysicValues) ballPhysic(state phyStateInt) BallPhysic {
+	// jump cut 0 is fixed jump height like 1
+	jumpCut := v.JumpCut
+	if jumpCut == 0 {
+		jumpCut = 1
+	}
+
+	return BallPhysic{
+		state:           state,
+		gravity:         v.Gravity,
+		friction:        v.Friction,
+		radius:          v.Radius,
+		speedRun:        v.SpeedRun,
+		jump:            v.Jump,
+		jumpForce:       v.JumpForce,
+		scrambleWall:    v.ScrambleWall,
+		bounceFactor:    v.BounceFactor,
+		coyoteTicks:     v.CoyoteTicks,
+		jumpBufferTicks: v.JumpBufferTicks,
+		jumpCut:         jumpCut,
+		materials:       maps.Clone(v.Materials),
+	}
+}
+
+// newPhysics default physics file, all difficulties use defaultBallPhysicA and defaultBallPhysicB
+func newPhysics() *Physics {
+	// floaty changes only movement of default physics
+	floatyA := physicValues(defaultBallPhysicA)
+	floatyA.Gravity = 1
+	floatyA.Friction = 0.98
+	floatyA.SpeedRun = 1
+	floatyA.Jump = Vector{0, -0.8}
+	floatyB := physicValues(defaultBallPhysicB)
+	floatyB.Gravity = 0.5
+	floatyB.Friction = 0.1
+	floatyB.Jump = Vector{}
+	floatyB.JumpForce = 10
+	floatyB.ScrambleWall = Vector{0, -0.5}
+
+	return &Physics{
+		Presets: []PhysicsPreset{
+			{
+				Name: defaultPhysicsPreset,
+				A:    physicValues(defaultBallPhysicA),
+				B:    physicValues(defaultBallPhysicB),
+			},
+			{
+				Name: "floaty",
+				A:    floatyA,
+				B:    floatyB,
+			},
+		},
+		Difficulty: []string{defaultPhysicsPreset, defaultPhysicsPreset, defaultPhysicsPreset},
+	}
+}
+
+// LoadPhysics load physics file or create it with default presets
+func LoadPhysics() (*Physics, error) {
+	physics := newPhysics()
+	physicsFilePath := filepath.Join(GameFilesDir, physicsFileName)
+
+	err := physics.load(physicsFilePath)
+	switch {
+	case err == nil:
+		return physics, nil
+	case errors.Is(err, os.ErrNotExist):
+		// File doesn't exist - create with default
+		file, err := json.MarshalIndent(physics, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		err = os.WriteFile(physicsFilePath, file, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize physics file: %w", err)
+		}
+		return physics, nil
+	default:
+		return nil, fmt.Errorf("failed to load physics: %w", err)
+	}
+}
+
+// load read presets from file
+func (p *Physics) load(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	loaded := Physics{}
+	err = json.Unmarshal(file, &loaded)
+	if err != nil {
+		return err
+	}
+	// default preset is used for unknown names, file without it would leave the ball without physics
+	if _, err := loaded.preset(defaultPhysicsPreset); err != nil {
+		return err
+	}
+
+	p.Presets, p.Difficulty = loaded.Presets, loaded.Difficulty
+	p.modTime = info.ModTime()
+	return nil
+}
+
+// preset find preset by name, default preset is used if name is empty or unknown
+func (p *Physics) preset(name string) (PhysicsPreset, error) {
+	for _, preset := range p.Presets {
+		if preset.Name == name {
+			return preset, nil
+		}
+	}
+	for _, preset := range p.Presets {
+		if preset.Name == defaultPhysicsPreset {
+			return preset, nil
+		}
+	}
+
+	return PhysicsPreset{}, fmt.Errorf("physics preset %q not found", name)
+}
+
+// presetName preset of the level, or of the difficulty if level doesn't set one
+func (p *Physics) presetName(level *Level, difficulty int) string {
+	if level != nil && level.Physics != "" {
+		return level.Physics
+	}
+	if difficulty >= 0 && difficulty < len(p.Difficulty) {
+		return p.Difficulty[difficulty]
+	}
+	return defaultPhysicsPreset
+}
+
+// applyPhysics set ballPhysicA and ballPhysicB from preset of current level
+func (g *Game) applyPhysics() error {
+	preset, err := g.physics.preset(g.physics.presetName(g.getCurrentLevel(), g.score.CurrentDifficulty))
+	if err != nil {
+		return err
+	}
+
+	ballPhysicA = preset.A.ballPhysic(phyStateA)
+	ballPhysicB = preset.B.ballPhysic(phyStateB)
+	updateFormPhysics()
+
+	return nil
+}
+
+// updatePhysicsReload reload physics file if it is changed, designers can tweak values while game runs
+func (g *Game) updatePhysicsReload() error {
+	g.physics.ticks++
+	if g.physics.ticks < physicsReloadTicks {
+		return nil
+	}
+	g.physics.ticks = 0
+
+	physicsFilePath := filepath.Join(GameFilesDir, physicsFileName)
+	info, err := os.Stat(physicsFilePath)
+	if err != nil || info.ModTime().Equal(g.physics.modTime) {
+		return nil
+	}
+
+	err = g.physics.load(physicsFilePath)
+	if err != nil {
+		// keep old presets until the file is fixed
+		g.physics.modTime = info.ModTime()
+		log.Println("failed to reload physics:", err)
+		return nil
+	}
+
+	return g.applyPhysics()
+}
@@ -0,0 +1,117 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// oldPhysicsFile physics file written before jump timing and materials were added
+const oldPhysicsFile = `{
+  "presets": [
+    {
+      "name": "default",
+      "a": {"gravity": 0.95, "friction": 0.9, "radius": 30, "speedRun": 2, "jump": {"X": 0, "Y": -3}, "jumpForce": 10, "scrambleWall": {"X": 0, "Y": 0}, "bounceFactor": 0},
+      "b": {"gravity": 1, "friction": 0, "radius": 45, "speedRun": 2, "jump": {"X": 0, "Y": -0.7}, "jumpForce": 20, "scrambleWall": {"X": 0, "Y": -3}, "bounceFactor": 0}
+    }
+  ],
+  "difficulty": ["default", "default", "default"]
+}`
+
+func TestPhysicsOldFileKeepsJumpTiming(t *testing.T) {
+	var physics Physics
+	if err := json.Unmarshal([]byte(oldPhysicsFile), &physics); err != nil {
+		t.Fatal(err)
+	}
+	preset, err := physics.preset(defaultPhysicsPreset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := preset.A.ballPhysic(phyStateA)
+	if a.coyoteTicks != defaultBallPhysicA.coyoteTicks {
+		t.Errorf("coyoteTicks = %d, want %d", a.coyoteTicks, defaultBallPhysicA.coyoteTicks)
+	}
+	if a.jumpBufferTicks != defaultBallPhysicA.jumpBufferTicks {
+		t.Errorf("jumpBufferTicks = %d, want %d", a.jumpBufferTicks, defaultBallPhysicA.jumpBufferTicks)
+	}
+	if a.jumpCut != defaultBallPhysicA.jumpCut {
+		t.Errorf("jumpCut = %v, want %v", a.jumpCut, defaultBallPhysicA.jumpCut)
+	}
+}
+
+func TestPhysicsFileValuesWin(t *testing.T) {
+	var preset PhysicsPreset
+	err := json.Unmarshal([]byte(`{"name": "custom", "a": {"gravity": 2, "coyoteTicks": 0}}`), &preset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if preset.A.Gravity != 2 {
+		t.Errorf("gravity = %v, want 2", preset.A.Gravity)
+	}
+	if preset.A.CoyoteTicks != 0 {
+		t.Errorf("coyoteTicks = %d, want 0 from file", preset.A.CoyoteTicks)
+	}
+	if preset.A.Radius != defaultBallPhysicA.radius || preset.B.Radius != defaultBallPhysicB.radius {
+		t.Errorf("radius = %v, %v, want defaults", preset.A.Radius, preset.B.Radius)
+	}
+}
+
+func TestPhysicsOldFileKeepsMaterials(t *testing.T) {
+	var physics Physics
+	if err := json.Unmarshal([]byte(oldPhysicsFile), &physics); err != nil {
+		t.Fatal(err)
+	}
+	preset, err := physics.preset(defaultPhysicsPreset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := preset.A.ballPhysic(phyStateA)
+	if got, want := a.material(surfaceRed), defaultBallPhysicA.materials[surfaceRed]; got != want {
+		t.Errorf("red material = %+v, want %+v", got, want)
+	}
+	b := preset.B.ballPhysic(phyStateB)
+	if got, want := b.material(surfaceRed), defaultBallPhysicB.materials[surfaceRed]; got != want {
+		t.Errorf("red material of state B = %+v, want %+v", got, want)
+	}
+}
+
+func TestPhysicsMaterialsMerge(t *testing.T) {
+	var preset PhysicsPreset
+	err := json.Unmarshal([]byte(`{"name": "custom", "a": {"materials": {"border": {"bounce": 0.3, "friction": 0.5}}}}`), &preset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	a := preset.A.ballPhysic(phyStateA)
+	if got, want := a.material(surfaceBorder), (Material{Bounce: 0.3, Friction: 0.5}); got != want {
+		t.Errorf("border material = %+v, want %+v", got, want)
+	}
+	if got, want := a.material(surfaceRed), defaultBallPhysicA.materials[surfaceRed]; got != want {
+		t.Errorf("red material = %+v, want %+v", got, want)
+	}
+	// default materials are not changed by the file
+	if _, ok := defaultBallPhysicA.materials[surfaceBorder]; ok {
+		t.Error("file material is added to default physics")
+	}
+}
+
+// TestPhysicsLoadKeepsPresetsWithoutDefault file without default preset is rejected and old presets stay
+func TestPhysicsLoadKeepsPresetsWithoutDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), physicsFileName)
+	err := os.WriteFile(path, []byte(`{"presets": [{"name": "floaty"}], "difficulty": ["floaty"]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	physics := newPhysics()
+	if err := physics.load(path); err == nil {
+		t.Fatal("file without default preset is loaded")
+	}
+	if _, err := physics.preset(defaultPhysicsPreset); err != nil {
+		t.Errorf("old presets are lost: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package game
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// cullMargin draw a bit more than camera sees, wide strokes don't pop at edges
+const cullMargin = 50
+
+var (
+	whiteImage = ebiten.NewImage(3, 3)
+	// whiteSubImage texture for triangles, the edge pixels are not used to avoid bleeding
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
+)
+
+func init() {
+	whiteImage.Fill(color.White)
+}
+
+// visibleRange return min and max X which camera sees
+func (c *Camera) visibleRange() (float64, float64) {
+	return c.X - cullMargin, c.X + c.viewWidth() + cullMargin
+}
+
+// isVisibleX check what X range is visible in camera
+func (c *Camera) isVisibleX(minX, maxX float64) bool {
+	left, right := c.visibleRange()
+	return maxX >= left && minX <= right
+}
+
+// visibleSegments return part of segments sorted by X which camera sees
+func visibleSegments(segments []Segment, camera *Camera) []Segment {
+	left, right := camera.visibleRange()
+
+	first := sort.Search(len(segments), func(i int) bool {
+		return segments[i].B.X >= left
+	})
+	last := sort.Search(len(segments), func(i int) bool {
+		return segments[i].A.X > right
+	})
+
+	return segments[first:last]
+}
+
+// strokeBatch collects segments of one color and draws them with one call
+type strokeBatch struct {
+	path vector.Path
+	// last point of path, connected segments make one polyline
+	last    Vector
+	started bool
+	// phase distance along dashed polyline from the start of the current dash
+	phase float64
+
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// add add segment in screen coordinates
+func (b *strokeBatch) add(seg *Segment, camera *Camera) {
+	if !b.started || b.last != seg.A {
+		b.path.MoveTo(camera.toScreen(seg.A))
+	}
+	b.path.LineTo(camera.toScreen(seg.B))
+
+	b.last = seg.B
+	b.started = true
+}
+
+// addDashed add segment as dashes, dashes continue on connected segments
+func (b *strokeBatch) addDashed(seg *Segment, camera *Camera, dash, gap float64) {
+	if !b.started || b.last != seg.A {
+		b.phase = 0
+	}
+	b.last = seg.B
+	b.started = true
+
+	length := seg.B.Sub(seg.A).Len()
+	if length == 0 {
+		return
+	}
+	dir := seg.B.Sub(seg.A).Mul(1 / length)
+
+	period := dash + gap
+	for t := -b.phase; t < length; t += period {
+		from, to := math.Max(t, 0), math.Min(t+dash, length)
+		if to <= from {
+			continue
+		}
+		b.path.MoveTo(camera.toScreen(seg.A.Add(dir.Mul(from))))
+		b.path.LineTo(camera.toScreen(seg.A.Add(dir.Mul(to))))
+	}
+	b.phase = math.Mod(b.phase+length, period)
+}
+
+// draw stroke all added segments and clean batch
+func (b *strokeBatch) draw(screen *ebiten.Image, width float32, c color.Color) {
+	if !b.started {
+		return
+	}
+
+	b.vertices, b.indices = b.path.AppendVerticesAndIndicesForStroke(b.vertices[:0], b.indices[:0], &vector.StrokeOptions{
+		Width:    width,
+		LineJoin: vector.LineJoinRound,
+	})
+
+	r, g, bl, a := c.RGBA()
+	for i := range b.vertices {
+		b.vertices[i].SrcX = 1
+		b.vertices[i].SrcY = 1
+		b.vertices[i].ColorR = float32(r) / 0xffff
+		b.vertices[i].ColorG = float32(g) / 0xffff
+		b.vertices[i].ColorB = float32(bl) / 0xffff
+		b.vertices[i].ColorA = float32(a) / 0xffff
+	}
+
+	screen.DrawTriangles(b.vertices, b.indices, whiteSubImage, &ebiten.DrawTrianglesOptions{
+		AntiAlias: true,
+	})
+
+	b.path = vector.Path{}
+	b.started = false
+	b.phase = 0
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// risingFloorProgress the player lingers while moving right less than this
+	risingFloorProgress = 400.0
+	// risingFloorLingerTicks ticks of lingering before the floor rises
+	risingFloorLingerTicks = 240
+	risingFloorSpeed       = 1.5
+	// risingFloorSink speed of sinking back while the player moves on
+	risingFloorSink = 3.0
+	// risingFloorSafe min distance under save point for saved floor
+	risingFloorSafe = 300.0
+	// risingFloorAlpha alpha of the fill under the floor
+	risingFloorAlpha = 0.3
+)
+
+// newRisingFloor margin call floor at the bottom of border square
+func newRisingFloor(y float64) *Segment {
+	return &Segment{
+		A:             Vector{0, y},
+		B:             Vector{0, y},
+		IsRisingFloor: true,
+	}
+}
+
+// updateRisingFloor rise the floor if the player lingers and sink it back if the player moves on
+func (g *Game) updateRisingFloor() {
+	bottomY := g.borderSquare.bottomY()
+	if g.risingFloor == nil {
+		g.risingFloor = newRisingFloor(bottomY)
+	}
+
+	if g.ball.pos.X > g.lingerX+risingFloorProgress {
+		g.lingerX = g.ball.pos.X
+		g.lingerTicks = 0
+	} else {
+		g.lingerTicks++
+	}
+
+	// Y axis goes down, rising is smaller Y
+	y := g.risingFloor.A.Y
+	if g.lingerTicks > risingFloorLingerTicks {
+		y -= risingFloorSpeed
+	} else {
+		y += risingFloorSink
+	}
+	if y > bottomY {
+		y = bottomY
+	}
+
+	// the floor is as wide as the border square
+	g.risingFloor.A = Vector{g.borderSquare.leftX(), y}
+	g.risingFloor.B = Vector{g.borderSquare.rightX(), y}
+}
+
+// drawRisingFloor draw the floor line and fill under it
+func (g *Game) drawRisingFloor(screen *ebiten.Image) {
+	if g.risingFloor == nil {
+		return
+	}
+
+	_, y := g.camera.toUI(g.risingFloor.A)
+	if y > g.screenHeight {
+		return
+	}
+
+	// premultiplied wall color
+	alpha := risingFloorAlpha
+	fill := color.RGBA{
+		R: uint8(float64(wallColor.R) * alpha),
+		G: uint8(float64(wallColor.G) * alpha),
+		B: uint8(float64(wallColor.B) * alpha),
+		A: uint8(0xff * alpha),
+	}
+	uiFillRect(screen, 0, float32(y), float32(g.screenWidth), float32(g.screenHeight-y), fill)
+	left, right := g.camera.visibleRange()
+	g.camera.strokeLine(screen, Vector{left, g.risingFloor.A.Y}, Vector{right, g.risingFloor.A.Y}, segmentWidth, wallColor)
+}
@@ -1,5 +1,14 @@
 package game
 
+const (
+	// savePointRadius radius of save points on the ground
+	savePointRadius = 20.0
+	// savePointGroundOffset distance from the segment to its save point along the normal
+	savePointGroundOffset = 15.0
+	// finishRadius radius of the last save point, the biggest one
+	finishRadius = 50.0
+)
+
 // SavePoint is the place where the game automatically saves the user
 type SavePoint struct {
 	Position      Vector  `json:"position"`
@@ -7,4 +16,5 @@ type SavePoint struct {
 	IsFinish      bool    `json:"isFinish"`
 	startPosition Vector
 	movingDown    bool
+	collected     bool
 }
@@ -0,0 +1,135 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// uiScale pixels of the screen image per screen unit, it is set by Layout.
+// UI and camera work in screen units, only drawing functions multiply by uiScale.
+var uiScale = 1.0
+
+// Layout fit ScreenWidth x ScreenHeight units into the window and give extra space to the longer side,
+// the screen is never smaller than ScreenWidth x ScreenHeight units.
+// The screen image has real pixels of the window, so the game is sharp on HiDPI monitors.
+func (g *Game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	g.screenWidth, g.screenHeight = fitScreen(float64(outsideWidth), float64(outsideHeight))
+
+	deviceScale := 1.0
+	if monitor := ebiten.Monitor(); monitor != nil {
+		deviceScale = monitor.DeviceScaleFactor()
+	}
+	uiScale = deviceScale
+	if outsideWidth > 0 {
+		uiScale = float64(outsideWidth) * deviceScale / g.screenWidth
+	}
+
+	return int(math.Ceil(g.screenWidth * uiScale)), int(math.Ceil(g.screenHeight * uiScale))
+}
+
+// fitScreen return screen size for window size
+func fitScreen(outsideWidth, outsideHeight float64) (float64, float64) {
+	if outsideWidth <= 0 || outsideHeight <= 0 {
+		return ScreenWidth, ScreenHeight
+	}
+
+	scale := math.Min(outsideWidth/ScreenWidth, outsideHeight/ScreenHeight)
+	return math.Floor(outsideWidth / scale), math.Floor(outsideHeight / scale)
+}
+
+// uiLeft X of the left edge of ScreenWidth wide UI in the center of screen
+func (g *Game) uiLeft() float64 {
+	return (g.screenWidth - ScreenWidth) / 2
+}
+
+// uiCenterX X of the center of screen
+func (g *Game) uiCenterX() float64 {
+	return g.screenWidth / 2
+}
+
+// drawMenuBackground stretch menu background to the whole screen
+func (g *Game) drawMenuBackground(screen *ebiten.Image) {
+	options := &ebiten.DrawImageOptions{}
+	options.GeoM.Scale(g.screenWidth/ScreenWidth, g.screenHeight/ScreenHeight)
+	uiDrawImage(screen, g.menuBg, options)
+}
+
+// uiCursorPosition cursor position in screen units
+func uiCursorPosition() (float64, float64) {
+	x, y := ebiten.CursorPosition()
+	return float64(x) / uiScale, float64(y) / uiScale
+}
+
+// uiFillRect vector.DrawFilledRect in screen units
+func uiFillRect(screen *ebiten.Image, x, y, width, height float32, clr color.Color) {
+	s := float32(uiScale)
+	vector.DrawFilledRect(screen, x*s, y*s, width*s, height*s, clr, false)
+}
+
+// uiStrokeRect vector.StrokeRect in screen units
+func uiStrokeRect(screen *ebiten.Image, x, y, width, height, strokeWidth float32, clr color.Color) {
+	s := float32(uiScale)
+	vector.StrokeRect(screen, x*s, y*s, width*s, height*s, strokeWidth*s, clr, false)
+}
+
+// uiFillCircle vector.DrawFilledCircle in screen units
+func uiFillCircle(screen *ebiten.Image, x, y, radius float32, clr color.Color) {
+	s := float32(uiScale)
+	vector.DrawFilledCircle(screen, x*s, y*s, radius*s, clr, false)
+}
+
+// uiStrokeLine vector.StrokeLine in screen units
+func uiStrokeLine(screen *ebiten.Image, x0, y0, x1, y1, strokeWidth float32, clr color.Color) {
+	s := float32(uiScale)
+	vector.StrokeLine(screen, x0*s, y0*s, x1*s, y1*s, strokeWidth*s, clr, false)
+}
+
+// uiDrawImage draw image with options in screen units
+func uiDrawImage(screen, image *ebiten.Image, options *ebiten.DrawImageOptions) {
+	options.GeoM.Scale(uiScale, uiScale)
+	screen.DrawImage(image, options)
+}
+
+// uiDrawText draw text with options in screen units, the face is drawn in real size, so text is sharp
+func uiDrawText(screen *ebiten.Image, str string, face text.Face, options *text.DrawOptions) {
+	if f, ok := face.(*text.GoTextFace); ok {
+		scaled := *f
+		scaled.Size *= uiScale
+		face = &scaled
+
+		// glyphs of scaled face are already in pixels, only position is scaled
+		geoM := ebiten.GeoM{}
+		geoM.Scale(1/uiScale, 1/uiScale)
+		geoM.Concat(options.GeoM)
+		options.GeoM = geoM
+	}
+	options.GeoM.Scale(uiScale, uiScale)
+	text.Draw(screen, str, face, options)
+}
+
+// uiVertices scale vertices from screen units to pixels
+func uiVertices(vertices []ebiten.Vertex) {
+	for i := range vertices {
+		vertices[i].DstX *= float32(uiScale)
+		vertices[i].DstY *= float32(uiScale)
+	}
+}
+
+// windowSize return window size for scale, the window is not bigger than monitor
+func windowSize(scale float64) (int, int) {
+	width, height := ScreenWidth*scale, ScreenHeight*scale
+
+	if monitor := ebiten.Monitor(); monitor != nil {
+		monitorWidth, monitorHeight := monitor.Size()
+		if monitorWidth > 0 && monitorHeight > 0 {
+			fit := math.Min(1, math.Min(float64(monitorWidth)/width, float64(monitorHeight)/height))
+			width, height = width*fit, height*fit
+		}
+	}
+
+	return int(width), int(height)
+}
@@ -10,7 +10,21 @@ type Segment struct {
 	savePoint    *SavePoint
 	isRed        bool
 	IsMovingWall bool `json:"isMovingWall"`
-	isBorder     bool
+	// IsRisingFloor margin call floor which rises when the player lingers
+	IsRisingFloor bool `json:"isRisingFloor,omitempty"`
+	isBorder      bool
+}
+
+// surface material name of the segment
+func (s Segment) surface() string {
+	switch {
+	case s.isBorder:
+		return surfaceBorder
+	case s.isRed:
+		return surfaceRed
+	default:
+		return surfaceGround
+	}
 }
 
 func (s Segment) Normal() Vector {
@@ -0,0 +1,208 @@
+package game
+
+import (
+	"ball/assets"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// settingsFileName - file with user settings
+const settingsFileName = "settings.json"
+
+// windowScales available window scales
+var windowScales = []float64{0.5, 0.75, 1, 1.25, 1.5}
+
+// Settings user options, saved in settings file
+type Settings struct {
+	MusicVolume float64 `json:"musicVolume"`
+	SFXVolume   float64 `json:"sfxVolume"`
+	Fullscreen  bool    `json:"fullscreen"`
+	WindowScale float64 `json:"windowScale"`
+	VSync       bool    `json:"vsync"`
+	// Theme name of color theme
+	Theme string `json:"theme"`
+	// HazardPattern draw hazards dashed and crossed, not only by color
+	HazardPattern bool `json:"hazardPattern"`
+
+	// HUD
+	ShowScore       bool `json:"showScore"`
+	ShowEnemyMarker bool `json:"showEnemyMarker"`
+	ShowMinimap     bool `json:"showMinimap"`
+
+	// appliedWindowScale scale of the last window resize, the window keeps size set by the user until scale changes
+	appliedWindowScale float64
+}
+
+func newSettings() *Settings {
+	return &Settings{
+		MusicVolume:     0.5,
+		SFXVolume:       0.5,
+		WindowScale:     1,
+		Theme:           defaultThemeName,
+		VSync:           true,
+		ShowScore:       true,
+		ShowEnemyMarker: true,
+		ShowMinimap:     true,
+	}
+}
+
+// LoadSettings loads the settings from file or initializes with default value
+func LoadSettings() (*Settings, error) {
+	settings := newSettings()
+	settingsFilePath := filepath.Join(GameFilesDir, settingsFileName)
+	file, err := os.ReadFile(settingsFilePath)
+	if err == nil {
+		err = json.Unmarshal(file, settings)
+	}
+
+	switch {
+	case err == nil:
+		return settings, nil
+	case errors.Is(err, os.ErrNotExist):
+		// File doesn't exist - create with default
+		err = settings.save()
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize settings file: %w", err)
+		}
+		return settings, nil
+	default:
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+}
+
+// save write settings to file
+func (s *Settings) save() error {
+	file, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(GameFilesDir, settingsFileName), file, 0644)
+}
+
+// Apply apply window and palette settings
+func (s *Settings) Apply() {
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
+	if s.WindowScale != s.appliedWindowScale {
+		ebiten.SetWindowSize(windowSize(s.WindowScale))
+		s.appliedWindowScale = s.WindowScale
+	}
+	ebiten.SetFullscreen(s.Fullscreen)
+	ebiten.SetVsyncEnabled(s.VSync)
+
+	setTheme(findTheme(s.Theme))
+}
+
+// save apply settings and save them in file
+func (g *Game) saveSettings() error {
+	g.settings.Apply()
+	g.menuBg.Fill(playBackground)
+	g.audio.SetMusicVolume(g.settings.MusicVolume)
+	g.audio.SetSoundVolume(g.settings.SFXVolume)
+
+	return g.settings.save()
+}
+
+// openSettings show settings, Escape returns to the current state
+func (g *Game) openSettings() {
+	g.settingsReturnState = g.currentState
+	g.currentState = StateSettings
+}
+
+// updateSettings process keyboard in settings
+func (g *Game) updateSettings() {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		g.currentState = g.settingsReturnState
+	}
+}
+
+// nextVolume increase volume by 10%, after 100% goes 0%
+func nextVolume(volume float64) float64 {
+	volume += 0.1
+	if volume > 1.05 {
+		return 0
+	}
+	return volume
+}
+
+// nextWindowScale return the next scale from windowScales
+func nextWindowScale(scale float64) float64 {
+	for i, s := range windowScales {
+		if s == scale {
+			return windowScales[(i+1)%len(windowScales)]
+		}
+	}
+	return 1
+}
+
+func onOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
+}
+
+func (g *Game) drawSettings(screen *ebiten.Image) {
+	g.drawMenuBackground(screen)
+
+	// Draw title
+	title := "SETTINGS"
+	w, h := text.Measure(title, assets.ScoreFaceBig, 0)
+	options := &text.DrawOptions{}
+	options.GeoM.Translate(g.uiCenterX()-w/2, 50-h/2)
+	options.ColorScale.ScaleWithColor(color.White)
+	uiDrawText(screen, title, assets.ScoreFaceBig, options)
+
+	s := g.settings
+	rows := []struct {
+		name   string
+		value  string
+		action func()
+	}{
+		{"MUSIC", fmt.Sprintf("%d%%", int(s.MusicVolume*100+0.5)), func() { s.MusicVolume = nextVolume(s.MusicVolume) }},
+		{"SOUND", fmt.Sprintf("%d%%", int(s.SFXVolume*100+0.5)), func() { s.SFXVolume = nextVolume(s.SFXVolume) }},
+		{"FULLSCREEN", onOff(s.Fullscreen), func() { s.Fullscreen = !s.Fullscreen }},
+		{"WINDOW SCALE", fmt.Sprintf("%d%%", int(s.WindowScale*100)), func() { s.WindowScale = nextWindowScale(s.WindowScale) }},
+		{"VSYNC", onOff(s.VSync), func() { s.VSync = !s.VSync }},
+		{"THEME", strings.ToUpper(findTheme(s.Theme).Name), func() { s.Theme = nextTheme(s.Theme) }},
+		{"HAZARD PATTERN", onOff(s.HazardPattern), func() { s.HazardPattern = !s.HazardPattern }},
+		{"HUD SCORE", onOff(s.ShowScore), func() { s.ShowScore = !s.ShowScore }},
+		{"HUD ENEMY", onOff(s.ShowEnemyMarker), func() { s.ShowEnemyMarker = !s.ShowEnemyMarker }},
+		{"HUD MINIMAP", onOff(s.ShowMinimap), func() { s.ShowMinimap = !s.ShowMinimap }},
+	}
+
+	for i, row := range rows {
+		y := 90 + float64(i)*64
+
+		nameBtn := Button{
+			X: g.uiCenterX() - 310, Y: y, Width: 400, Height: 60,
+			Text:       row.name,
+			Color:      groundColor,
+			HoverColor: groundColor,
+		}
+		drawButtonText(screen, &nameBtn)
+
+		valueBtn := Button{
+			X: g.uiCenterX() + 100, Y: y, Width: 210, Height: 60,
+			Text:       row.value,
+			Color:      ballColor,
+			HoverColor: ballColorBig,
+		}
+		drawButtonText(screen, &valueBtn)
+		if valueBtn.IsClicked() {
+			row.action()
+			g.drawError = g.saveSettings()
+		}
+	}
+
+	// Draw return button
+	g.drawReturnButton(screen, g.settingsReturnState)
+}
@@ -0,0 +1,52 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSettingsKeepOffValues options turned off and zero volume are loaded as saved, not as defaults
+func TestSettingsKeepOffValues(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir(GameFilesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	saved := Settings{
+		MusicVolume: 0,
+		SFXVolume:   0,
+		WindowScale: 1.5,
+		Theme:       "protanopia",
+	}
+	if err := saved.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != saved {
+		t.Errorf("loaded %+v, want %+v", *loaded, saved)
+	}
+}
+
+// TestSettingsDefault file is created with defaults on the first start
+func TestSettingsDefault(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir(GameFilesDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadSettings()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *loaded != *newSettings() {
+		t.Errorf("loaded %+v, want defaults %+v", *loaded, *newSettings())
+	}
+	if _, err := os.Stat(filepath.Join(GameFilesDir, settingsFileName)); err != nil {
+		t.Errorf("settings file is not created: %v", err)
+	}
+}
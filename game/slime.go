@@ -0,0 +1,141 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// slimeSpring, slimeDamping squash spring, squash goes back to 0
+	slimeSpring  = 0.2
+	slimeDamping = 0.25
+	// maxSquash max change of height, 0.4 is 40%
+	maxSquash = 0.4
+	// jumpStretch stretch on jump
+	jumpStretch = 0.3
+	// radiusLerp how fast drawn radius follows physical radius
+	radiusLerp = 0.2
+
+	// wobbleSpeed, wobbleAmount wobble of inflated slime
+	wobbleSpeed  = 0.15
+	wobbleAmount = 0.06
+	// slimePoints points of slime outline
+	slimePoints = 32
+
+	eyeLerp = 0.15
+)
+
+var (
+	eyeColor   = color.RGBA{255, 255, 255, 255}
+	pupilColor = color.RGBA{20, 20, 30, 255}
+)
+
+// slimeAnim animation of the ball, it changes only drawing, not physics
+type slimeAnim struct {
+	// radius drawn radius
+	radius float64
+	// squash < 0 is squashed, > 0 is stretched
+	squash    float64
+	squashVel float64
+	// wobble phase of wobble
+	wobble float64
+	// look -1 left, 1 right
+	look float64
+
+	path     vector.Path
+	vertices []ebiten.Vertex
+	indices  []uint16
+}
+
+// land squash by landing impact
+func (a *slimeAnim) land(impact float64) {
+	a.squash = -math.Min(impact/30, maxSquash)
+	a.squashVel = 0
+}
+
+// jump stretch up
+func (a *slimeAnim) jump() {
+	a.squash = jumpStretch
+	a.squashVel = 0
+}
+
+func (a *slimeAnim) update(b *Ball) {
+	a.radius += (b.radius - a.radius) * radiusLerp
+
+	a.squashVel += -a.squash*slimeSpring - a.squashVel*slimeDamping
+	a.squash = math.Max(-maxSquash, math.Min(maxSquash, a.squash+a.squashVel))
+
+	if b.currPhyState.state == phyStateB {
+		a.wobble += wobbleSpeed
+	}
+
+	look := -1.0
+	if b.facingRight {
+		look = 1
+	}
+	a.look += (look - a.look) * eyeLerp
+}
+
+// wobbleScale scale of radius at angle, inflated slime wobbles
+func (a *slimeAnim) wobbleScale(angle float64, inflated bool) float64 {
+	if !inflated {
+		return 1
+	}
+	return 1 + wobbleAmount*math.Sin(3*angle+a.wobble)*math.Sin(a.wobble*0.7)
+}
+
+// drawSlime draw ball as a slime with eyes
+func (b *Ball) drawSlime(screen *ebiten.Image, camera *Camera, c color.Color) {
+	a := &b.anim
+	inflated := b.currPhyState.state == phyStateB
+
+	// keep area, the bottom stays at the same place
+	scaleY := 1 + a.squash
+	scaleX := 1 / scaleY
+	center := b.pos.Add(Vector{0, a.radius * (1 - scaleY)})
+
+	a.path = vector.Path{}
+	for i := 0; i < slimePoints; i++ {
+		angle := 2 * math.Pi * float64(i) / slimePoints
+		r := a.radius * a.wobbleScale(angle, inflated)
+		x, y := camera.toScreen(center.Add(Vector{math.Cos(angle) * r * scaleX, math.Sin(angle) * r * scaleY}))
+		if i == 0 {
+			a.path.MoveTo(x, y)
+		} else {
+			a.path.LineTo(x, y)
+		}
+	}
+	a.path.Close()
+
+	a.vertices, a.indices = a.path.AppendVerticesAndIndicesForFilling(a.vertices[:0], a.indices[:0])
+	r, g, bl, al := c.RGBA()
+	for i := range a.vertices {
+		a.vertices[i].SrcX = 1
+		a.vertices[i].SrcY = 1
+		a.vertices[i].ColorR = float32(r) / 0xffff
+		a.vertices[i].ColorG = float32(g) / 0xffff
+		a.vertices[i].ColorB = float32(bl) / 0xffff
+		a.vertices[i].ColorA = float32(al) / 0xffff
+	}
+	screen.DrawTriangles(a.vertices, a.indices, whiteSubImage, &ebiten.DrawTrianglesOptions{
+		AntiAlias: true,
+	})
+
+	if b.form != nil && b.form.decorate != nil {
+		b.form.decorate(screen, camera, center, a.radius)
+	}
+
+	// eyes look in direction of movement
+	eyeRadius := a.radius * 0.22
+	for _, side := range []float64{-1, 1} {
+		eye := center.Add(Vector{
+			(side*0.35 + a.look*0.25) * a.radius * scaleX,
+			-0.25 * a.radius * scaleY,
+		})
+		camera.drawCircle(screen, eye, eyeRadius, eyeColor)
+		camera.drawCircle(screen, eye.Add(Vector{a.look * eyeRadius * 0.4, 0}), eyeRadius*0.5, pupilColor)
+	}
+}
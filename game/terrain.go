@@ -0,0 +1,101 @@
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// terrainAlpha alpha of the fill right under the chart line, it fades to 0 at the bottom
+const terrainAlpha = 0.5
+
+// terrainMesh cached filled area under a ground chunk
+type terrainMesh struct {
+	// the mesh is rebuilt when any of these changes
+	first    *Segment
+	count    int
+	bottomY  float64
+	up, down color.RGBA
+
+	// vertices in level coordinates
+	vertices []ebiten.Vertex
+	indices  []uint16
+	// screenVertices vertices in screen coordinates, reused every frame
+	screenVertices []ebiten.Vertex
+}
+
+// terrainVertex vertex with premultiplied color
+func terrainVertex(x, y float64, c color.RGBA, alpha float32) ebiten.Vertex {
+	return ebiten.Vertex{
+		DstX:   float32(x),
+		DstY:   float32(y),
+		SrcX:   1,
+		SrcY:   1,
+		ColorR: float32(c.R) / 0xff * alpha,
+		ColorG: float32(c.G) / 0xff * alpha,
+		ColorB: float32(c.B) / 0xff * alpha,
+		ColorA: alpha,
+	}
+}
+
+// update rebuild mesh if ground, bottom or colors changed
+// up - color above start price, down - color below start price
+func (m *terrainMesh) update(ground []*Segment, bottomY, startY float64, up, down color.RGBA) {
+	if len(ground) == 0 {
+		m.first, m.count = nil, 0
+		m.vertices, m.indices = m.vertices[:0], m.indices[:0]
+		return
+	}
+	if m.first == ground[0] && m.count == len(ground) && m.bottomY == bottomY && m.up == up && m.down == down {
+		return
+	}
+
+	m.first, m.count, m.bottomY, m.up, m.down = ground[0], len(ground), bottomY, up, down
+	m.vertices, m.indices = m.vertices[:0], m.indices[:0]
+
+	tint := func(y float64) color.RGBA {
+		// Y axis goes down, price above start has smaller Y
+		if y <= startY {
+			return up
+		}
+		return down
+	}
+
+	for _, seg := range ground {
+		i := uint16(len(m.vertices))
+		m.vertices = append(m.vertices,
+			terrainVertex(seg.A.X, seg.A.Y, tint(seg.A.Y), terrainAlpha),
+			terrainVertex(seg.B.X, seg.B.Y, tint(seg.B.Y), terrainAlpha),
+			terrainVertex(seg.B.X, bottomY, tint(seg.B.Y), 0),
+			terrainVertex(seg.A.X, bottomY, tint(seg.A.Y), 0),
+		)
+		m.indices = append(m.indices, i, i+1, i+2, i, i+2, i+3)
+	}
+}
+
+func (m *terrainMesh) draw(screen *ebiten.Image, camera *Camera) {
+	if len(m.vertices) == 0 {
+		return
+	}
+
+	m.screenVertices = append(m.screenVertices[:0], m.vertices...)
+	for i := range m.screenVertices {
+		v := &m.screenVertices[i]
+		v.DstX, v.DstY = camera.toScreen(Vector{float64(v.DstX), float64(v.DstY)})
+	}
+
+	screen.DrawTriangles(m.screenVertices, m.indices, whiteSubImage, nil)
+}
+
+// drawTerrain draw filled area under both ground buffers
+func (g *Game) drawTerrain(screen *ebiten.Image) {
+	if g.borderSquare == nil || len(g.ground) == 0 {
+		return
+	}
+
+	startY := g.ground[0].A.Y
+	for i := range g.groundBuff {
+		g.terrain[i].update(g.groundBuff[i], g.borderSquare.bottomY(), startY, savePointColor, wallColor)
+		g.terrain[i].draw(screen, g.camera)
+	}
+}
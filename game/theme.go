@@ -0,0 +1,192 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const (
+	// themesDir - user themes, one json file per theme
+	themesDir        = "themes"
+	defaultThemeName = "default"
+
+	// hazardDash, hazardGap dashes of red segments when hazard pattern is on
+	hazardDash = 24.0
+	hazardGap  = 16.0
+)
+
+// Theme colors of the game, colors are json objects {"R":0,"G":0,"B":0,"A":255}
+type Theme struct {
+	Name        string     `json:"name"`
+	Background  color.RGBA `json:"background"`
+	Wall        color.RGBA `json:"wall"`
+	WallHover   color.RGBA `json:"wallHover"`
+	SavePoint   color.RGBA `json:"savePoint"`
+	Ground      color.RGBA `json:"ground"`
+	GroundHover color.RGBA `json:"groundHover"`
+	Ball        color.RGBA `json:"ball"`
+	BallBig     color.RGBA `json:"ballBig"`
+	// Hazard red segments and borders
+	Hazard      color.RGBA `json:"hazard"`
+	HazardHover color.RGBA `json:"hazardHover"`
+	// Bear, Bull, Sticky colors of slime forms
+	Bear   color.RGBA `json:"bear"`
+	Bull   color.RGBA `json:"bull"`
+	Sticky color.RGBA `json:"sticky"`
+}
+
+// defaultTheme keeps initial colors
+var defaultTheme = Theme{
+	Name:        defaultThemeName,
+	Background:  playBackground,
+	Wall:        wallColor,
+	WallHover:   wallColorHover,
+	SavePoint:   savePointColor,
+	Ground:      groundColor,
+	GroundHover: groundColorHover,
+	Ball:        ballColor,
+	BallBig:     ballColorBig,
+	Hazard:      yellowColor,
+	HazardHover: yellowColorHover,
+	Bear:        bearColor,
+	Bull:        bullColor,
+	Sticky:      stickyColor,
+}
+
+// themes built-in presets and themes loaded from themesDir
+var themes = []Theme{
+	defaultTheme,
+	// red and green are replaced by vermilion and blue
+	{
+		Name:        "deuteranopia",
+		Background:  playBackground,
+		Wall:        color.RGBA{213, 94, 0, 255},
+		WallHover:   color.RGBA{233, 124, 30, 255},
+		SavePoint:   color.RGBA{86, 180, 233, 255},
+		Ground:      groundColor,
+		GroundHover: groundColorHover,
+		Ball:        color.RGBA{0, 114, 178, 255},
+		BallBig:     color.RGBA{40, 144, 208, 200},
+		Hazard:      color.RGBA{240, 228, 66, 255},
+		HazardHover: color.RGBA{250, 240, 110, 255},
+		Bear:        color.RGBA{204, 121, 167, 255},
+		Bull:        color.RGBA{230, 159, 0, 255},
+		Sticky:      color.RGBA{0, 158, 115, 255},
+	},
+	// red looks dark, so wall is bright orange
+	{
+		Name:        "protanopia",
+		Background:  playBackground,
+		Wall:        color.RGBA{230, 159, 0, 255},
+		WallHover:   color.RGBA{250, 185, 40, 255},
+		SavePoint:   color.RGBA{86, 180, 233, 255},
+		Ground:      groundColor,
+		GroundHover: groundColorHover,
+		Ball:        color.RGBA{0, 114, 178, 255},
+		BallBig:     color.RGBA{40, 144, 208, 200},
+		Hazard:      color.RGBA{240, 228, 66, 255},
+		HazardHover: color.RGBA{250, 240, 110, 255},
+		Bear:        color.RGBA{204, 121, 167, 255},
+		Bull:        color.RGBA{210, 210, 210, 255},
+		Sticky:      color.RGBA{0, 158, 115, 255},
+	},
+	{
+		Name:        "high contrast",
+		Background:  color.RGBA{0, 0, 0, 255},
+		Wall:        color.RGBA{255, 0, 90, 255},
+		WallHover:   color.RGBA{255, 80, 140, 255},
+		SavePoint:   color.RGBA{0, 255, 0, 255},
+		Ground:      color.RGBA{90, 90, 90, 255},
+		GroundHover: color.RGBA{140, 140, 140, 255},
+		Ball:        color.RGBA{0, 220, 255, 255},
+		BallBig:     color.RGBA{120, 240, 255, 220},
+		Hazard:      color.RGBA{255, 255, 0, 255},
+		HazardHover: color.RGBA{255, 255, 140, 255},
+		Bear:        color.RGBA{255, 140, 0, 255},
+		Bull:        color.RGBA{255, 255, 255, 255},
+		Sticky:      color.RGBA{0, 255, 120, 255},
+	},
+}
+
+// LoadThemes add themes from themesDir, missing colors are taken from default theme
+func LoadThemes() error {
+	dirEntry, err := os.ReadDir(filepath.Join(GameFilesDir, themesDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, e := range dirEntry {
+		if !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+
+		file, err := os.ReadFile(filepath.Join(GameFilesDir, themesDir, e.Name()))
+		if err != nil {
+			return err
+		}
+
+		theme := defaultTheme
+		theme.Name = strings.TrimSuffix(e.Name(), ".json")
+		err = json.Unmarshal(file, &theme)
+		if err != nil {
+			return fmt.Errorf("failed to load theme %s: %w", e.Name(), err)
+		}
+
+		themes = append(themes, theme)
+	}
+
+	return nil
+}
+
+// findTheme return theme by name or default theme
+func findTheme(name string) Theme {
+	for _, t := range themes {
+		if t.Name == name {
+			return t
+		}
+	}
+	return defaultTheme
+}
+
+// nextTheme return name of the theme after name
+func nextTheme(name string) string {
+	for i, t := range themes {
+		if t.Name == name {
+			return themes[(i+1)%len(themes)].Name
+		}
+	}
+	return defaultThemeName
+}
+
+func setTheme(t Theme) {
+	playBackground = t.Background
+	wallColor = t.Wall
+	wallColorHover = t.WallHover
+	savePointColor = t.SavePoint
+	groundColor = t.Ground
+	groundColorHover = t.GroundHover
+	ballColor = t.Ball
+	ballColorBig = t.BallBig
+	yellowColor = t.Hazard
+	yellowColorHover = t.HazardHover
+	bearColor = t.Bear
+	bullColor = t.Bull
+	stickyColor = t.Sticky
+}
+
+// drawHazardCross draw cross over the hazard circle, it doesn't depend on colors
+func (g *Game) drawHazardCross(screen *ebiten.Image, pos Vector, radius float64) {
+	r := radius * 0.6
+	g.camera.strokeLine(screen, pos.Add(Vector{-r, -r}), pos.Add(Vector{r, r}), 4, playBackground)
+	g.camera.strokeLine(screen, pos.Add(Vector{-r, r}), pos.Add(Vector{r, -r}), 4, playBackground)
+}
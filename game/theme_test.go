@@ -0,0 +1,27 @@
+package game
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestThemesSetFormColors every built-in theme gives own colors to all forms
+func TestThemesSetFormColors(t *testing.T) {
+	defer setTheme(defaultTheme)
+
+	for _, theme := range themes {
+		setTheme(theme)
+
+		colors := map[color.RGBA]string{}
+		for _, f := range forms {
+			c := f.color()
+			if c.A == 0 {
+				t.Errorf("theme %q: form %s has no color", theme.Name, f.Name)
+			}
+			if other, ok := colors[c]; ok {
+				t.Errorf("theme %q: forms %s and %s have the same color", theme.Name, f.Name, other)
+			}
+			colors[c] = f.Name
+		}
+	}
+}
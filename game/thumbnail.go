@@ -0,0 +1,79 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// thumbnailPadding keeps chart away from thumbnail edges
+const thumbnailPadding = 4
+
+// ChartThumbnail whole chart scaled down into image
+type ChartThumbnail struct {
+	image         *ebiten.Image
+	width, height float64
+
+	// chart bounds to map level positions on the thumbnail
+	minX, maxX float64
+	minY, maxY float64
+}
+
+// newChartThumbnail render chart points into image
+func newChartThumbnail(points []Vector, width, height int, lineWidth float32, background color.Color) *ChartThumbnail {
+	t := &ChartThumbnail{
+		image:  ebiten.NewImage(width, height),
+		width:  float64(width),
+		height: float64(height),
+		minX:   points[0].X,
+		maxX:   points[len(points)-1].X,
+		minY:   points[0].Y,
+		maxY:   points[0].Y,
+	}
+
+	for _, p := range points {
+		t.minY = math.Min(t.minY, p.Y)
+		t.maxY = math.Max(t.maxY, p.Y)
+	}
+
+	t.image.Fill(background)
+
+	// draw no more than one line per pixel
+	step := max(1, len(points)/width)
+	prev := t.toThumbnail(points[0])
+	for i := step; i < len(points); i += step {
+		curr := t.toThumbnail(points[i])
+		vector.StrokeLine(t.image,
+			float32(prev.X), float32(prev.Y),
+			float32(curr.X), float32(curr.Y),
+			lineWidth, savePointColor, true)
+		prev = curr
+	}
+
+	return t
+}
+
+// toThumbnail convert level position to thumbnail position
+func (t *ChartThumbnail) toThumbnail(pos Vector) Vector {
+	w := t.width - thumbnailPadding*2
+	h := t.height - thumbnailPadding*2
+
+	x, y := 0.0, 0.0
+	if t.maxX != t.minX {
+		x = (pos.X - t.minX) / (t.maxX - t.minX) * w
+	}
+	if t.maxY != t.minY {
+		y = (pos.Y - t.minY) / (t.maxY - t.minY) * h
+	}
+
+	return Vector{
+		X: thumbnailPadding + math.Max(0, math.Min(w, x)),
+		Y: thumbnailPadding + math.Max(0, math.Min(h, y)),
+	}
+}
+
+func (t *ChartThumbnail) deallocate() {
+	t.image.Deallocate()
+}
@@ -18,15 +18,23 @@ func getJsonName(fileName string) string {
 
 // calculateLevelProgress calculate percentage progress level
 func calculateLevelProgress(level Level) int {
-	if level.getFinished() {
+	return calculateDifficultyProgress(level, level.CurrentDifficulty)
+}
+
+// calculateDifficultyProgress calculate percentage progress level for difficulty
+func calculateDifficultyProgress(level Level, difficulty int) int {
+	entities := level.LevelEntities[difficulty]
+	if entities == nil {
+		return 0
+	} else if entities.Finished {
 		return 100
-	} else if level.getSavePoint() == nil {
+	} else if entities.SavePoint == nil {
 		return 0
 	} else {
 		if level.MaxX == 0 {
 			return 0
 		} else {
-			return int(level.getSavePoint().Position.X * 100 / level.MaxX)
+			return int(entities.SavePoint.Position.X * 100 / level.MaxX)
 		}
 	}
 }
@@ -216,7 +224,7 @@ func resetLevel(level *Level, game *Game) error {
 
 func returnToSelectLevel(game *Game) error {
 	game.currentState = StateLevelSelect
-	game.fractions = []Vector{}
+	game.particles.clear()
 
 	err := game.saveCurrentLevel()
 	if err != nil {
@@ -36,7 +36,58 @@ func SlopeAngleFromNormal(normal Vector) float64 {
 func closestPointOnSegment(a, b, p Vector) Vector {
 	ap := p.Sub(a)
 	ab := b.Sub(a)
+	// segment is a point
+	if ab.Dot(ab) == 0 {
+		return a
+	}
 	t := ap.Dot(ab) / ab.Dot(ab)
 	t = math.Max(0, math.Min(1, t))
 	return a.Add(ab.Mul(t))
 }
+
+// sweepCircleSegment time of impact of circle moving from p by d with segment ab,
+// t is 0..1 part of d, false if circle doesn't hit segment or already touches it
+func sweepCircleSegment(p, d Vector, r float64, a, b Vector) (float64, bool) {
+	if p.Sub(closestPointOnSegment(a, b, p)).Len() < r {
+		return 0, false
+	}
+
+	hit := math.MaxFloat64
+
+	// side of the segment
+	ab := b.Sub(a)
+	normal := Vector{-ab.Y, ab.X}.Normalize()
+	dist := p.Sub(a).Dot(normal)
+	if dist < 0 {
+		normal, dist = normal.Mul(-1), -dist
+	}
+	if speed := d.Dot(normal); speed < 0 {
+		t := (dist - r) / -speed
+		contact := p.Add(d.Mul(t)).Sub(normal.Mul(r))
+		if s := contact.Sub(a).Dot(ab) / ab.Dot(ab); t >= 0 && s >= 0 && s <= 1 {
+			hit = t
+		}
+	}
+
+	// ends of the segment
+	dd := d.Dot(d)
+	for _, end := range []Vector{a, b} {
+		if dd == 0 {
+			break
+		}
+		m := p.Sub(end)
+		md := m.Dot(d)
+		disc := md*md - dd*(m.Dot(m)-r*r)
+		if disc < 0 {
+			continue
+		}
+		if t := (-md - math.Sqrt(disc)) / dd; t >= 0 {
+			hit = math.Min(hit, t)
+		}
+	}
+
+	if hit > 1 {
+		return 0, false
+	}
+	return hit, true
+}
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSweepCircleSegment(t *testing.T) {
+	tests := []struct {
+		name   string
+		p, d   Vector
+		r      float64
+		a, b   Vector
+		wantT  float64
+		wantOk bool
+	}{
+		{
+			name:   "face hit",
+			p:      Vector{0, -50},
+			d:      Vector{0, 40},
+			r:      30,
+			a:      Vector{-100, 0},
+			b:      Vector{100, 0},
+			wantT:  0.5,
+			wantOk: true,
+		},
+		{
+			name:   "face hit from below",
+			p:      Vector{0, 50},
+			d:      Vector{0, -40},
+			r:      30,
+			a:      Vector{-100, 0},
+			b:      Vector{100, 0},
+			wantT:  0.5,
+			wantOk: true,
+		},
+		{
+			name:   "endpoint hit",
+			p:      Vector{-50, 0},
+			d:      Vector{40, 0},
+			r:      30,
+			a:      Vector{0, 0},
+			b:      Vector{100, 0},
+			wantT:  0.5,
+			wantOk: true,
+		},
+		{
+			name:   "miss beside segment",
+			p:      Vector{0, -50},
+			d:      Vector{0, 40},
+			r:      30,
+			a:      Vector{100, 0},
+			b:      Vector{200, 0},
+			wantOk: false,
+		},
+		{
+			name:   "miss too short motion",
+			p:      Vector{0, -100},
+			d:      Vector{0, 40},
+			r:      30,
+			a:      Vector{-100, 0},
+			b:      Vector{100, 0},
+			wantOk: false,
+		},
+		{
+			name:   "miss moving away",
+			p:      Vector{0, -50},
+			d:      Vector{0, -40},
+			r:      30,
+			a:      Vector{-100, 0},
+			b:      Vector{100, 0},
+			wantOk: false,
+		},
+		{
+			name:   "start overlaps",
+			p:      Vector{0, -10},
+			d:      Vector{0, 20},
+			r:      30,
+			a:      Vector{-100, 0},
+			b:      Vector{100, 0},
+			wantOk: false,
+		},
+		{
+			name:   "zero length segment hit",
+			p:      Vector{-50, 0},
+			d:      Vector{40, 0},
+			r:      30,
+			a:      Vector{0, 0},
+			b:      Vector{0, 0},
+			wantT:  0.5,
+			wantOk: true,
+		},
+		{
+			name:   "zero length segment overlaps",
+			p:      Vector{-10, 0},
+			d:      Vector{40, 0},
+			r:      30,
+			a:      Vector{0, 0},
+			b:      Vector{0, 0},
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotT, gotOk := sweepCircleSegment(tt.p, tt.d, tt.r, tt.a, tt.b)
+			if gotOk != tt.wantOk {
+				t.Fatalf("ok = %v, want %v (t = %v)", gotOk, tt.wantOk, gotT)
+			}
+			if gotOk && math.Abs(gotT-tt.wantT) > 1e-9 {
+				t.Errorf("t = %v, want %v", gotT, tt.wantT)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package game
+
+import (
+	"ball/assets"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// wallVolatilityRange part of chart ahead of the wall which makes volatility
+	wallVolatilityRange = 600.0
+	// wallVolatilityUnit average price jump of segment in pixels which is volatility 1
+	wallVolatilityUnit = 50.0
+	// wallDefaultVolatility extra speed at volatility 1 for levels without wall definition
+	wallDefaultVolatility = 0.5
+
+	// wallWarningSize size of the warning arrow
+	wallWarningSize = 24.0
+)
+
+// WallCurvePoint speed of the moving wall at distance from the ball
+type WallCurvePoint struct {
+	Distance float64 `json:"distance"`
+	Speed    float64 `json:"speed"`
+}
+
+// WallDefinition how the moving wall moves, levels can set it in json
+type WallDefinition struct {
+	// Curve points sorted by distance, speed between points is interpolated
+	Curve []WallCurvePoint `json:"curve"`
+	// Volatility extra speed at volatility 1 of the chart near the wall, negative slows the wall down
+	Volatility float64 `json:"volatility,omitempty"`
+}
+
+// defaultWallDefinition wall of the current difficulty: slow near the ball and fast far away
+func defaultWallDefinition() *WallDefinition {
+	return &WallDefinition{
+		Curve: []WallCurvePoint{
+			{Distance: 0, Speed: movWallSpeedSlow},
+			{Distance: ScreenWidth * 0.5, Speed: movWallSpeedSlow},
+			{Distance: ScreenWidth, Speed: (movWallSpeedSlow + movWallSpeedHight) / 2},
+			{Distance: ScreenWidth * 1.5, Speed: movWallSpeedHight},
+		},
+		Volatility: wallDefaultVolatility,
+	}
+}
+
+// speedAt speed of the curve at distance
+func (w *WallDefinition) speedAt(distance float64) float64 {
+	if len(w.Curve) == 0 {
+		return movWallSpeedSlow
+	}
+
+	i := sort.Search(len(w.Curve), func(i int) bool {
+		return w.Curve[i].Distance >= distance
+	})
+	if i == 0 {
+		return w.Curve[0].Speed
+	}
+	if i == len(w.Curve) {
+		return w.Curve[len(w.Curve)-1].Speed
+	}
+
+	a, b := w.Curve[i-1], w.Curve[i]
+	if b.Distance == a.Distance {
+		return b.Speed
+	}
+	t := (distance - a.Distance) / (b.Distance - a.Distance)
+	return a.Speed + (b.Speed-a.Speed)*t
+}
+
+// wallDefinition wall of the current level or of difficulty
+func (g *Game) wallDefinition() *WallDefinition {
+	if wall := g.getCurrentLevel().Wall; wall != nil {
+		return wall
+	}
+	return defaultWallDefinition()
+}
+
+// localVolatility average price jump of segments ahead of x, 1 is wallVolatilityUnit
+func (g *Game) localVolatility(x float64) float64 {
+	first := sort.Search(len(g.ground), func(i int) bool {
+		return g.ground[i].B.X >= x
+	})
+
+	sum := 0.0
+	count := 0
+	for i := first; i < len(g.ground) && g.ground[i].A.X < x+wallVolatilityRange; i++ {
+		sum += math.Abs(g.ground[i].B.Y - g.ground[i].A.Y)
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+
+	return sum / float64(count) / wallVolatilityUnit
+}
+
+// updateMovingWall move the wall by speed curve and volatility of the chart
+func (g *Game) updateMovingWall() {
+	wall := g.wallDefinition()
+	distance := g.ball.pos.X - g.movingWall.A.X
+
+	speed := wall.speedAt(math.Abs(distance)) + wall.Volatility*g.localVolatility(g.movingWall.A.X)
+	speed = math.Max(0, speed)
+
+	g.movingWall.A.X += speed
+	g.movingWall.B.X += speed
+
+	// the wall is closing if it is faster than the ball
+	g.wallClosing = speed > g.ball.vel.X
+	g.wallTicks++
+}
+
+// drawWallWarning draw arrow at the left edge if the wall is off-screen and closing
+func (g *Game) drawWallWarning(screen *ebiten.Image) {
+	wallX, _ := g.camera.toScreen(g.movingWall.A)
+	if wallX >= 0 || !g.wallClosing {
+		return
+	}
+
+	// blink faster when the wall is close
+	distance := g.ball.pos.X - g.movingWall.A.X
+	period := int(math.Max(10, math.Min(60, distance/ScreenWidth*30)))
+	if g.wallTicks%period < period/2 {
+		return
+	}
+
+	_, ballY := g.camera.toUI(g.ball.pos)
+	y := float32(math.Max(wallWarningSize*2, math.Min(g.screenHeight-wallWarningSize*2, ballY)))
+	x := float32(10)
+
+	var path vector.Path
+	path.MoveTo(x, y)
+	path.LineTo(x+wallWarningSize, y-wallWarningSize)
+	path.LineTo(x+wallWarningSize, y+wallWarningSize)
+	path.Close()
+	vertices, indices := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vertices {
+		vertices[i].SrcX, vertices[i].SrcY = 1, 1
+		vertices[i].ColorR = float32(wallColor.R) / 0xff
+		vertices[i].ColorG = float32(wallColor.G) / 0xff
+		vertices[i].ColorB = float32(wallColor.B) / 0xff
+		vertices[i].ColorA = 1
+	}
+	uiVertices(vertices)
+	screen.DrawTriangles(vertices, indices, whiteSubImage, &ebiten.DrawTrianglesOptions{AntiAlias: true})
+
+	options := &text.DrawOptions{}
+	options.GeoM.Translate(float64(x+wallWarningSize+8), float64(y)-12)
+	options.ColorScale.ScaleWithColor(wallColor)
+	uiDrawText(screen, fmt.Sprintf("WALL %d", int(distance/multiplyChartX)), assets.ScoreFace, options)
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestWallDefinitionSpeedAt(t *testing.T) {
+	var level Level
+	err := json.Unmarshal([]byte(`{
+		"ticker": "TEST",
+		"chartFile": "test.csv",
+		"wall": {"curve": [
+			{"distance": 100, "speed": 2},
+			{"distance": 300, "speed": 6},
+			{"distance": 500, "speed": 10}
+		]}
+	}`), &level)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// level wall overrides the default wall
+	game := &Game{levels: []*Level{&level}}
+	wall := game.wallDefinition()
+	if wall != level.Wall {
+		t.Fatal("level wall is not used")
+	}
+
+	tests := []struct {
+		name     string
+		wall     *WallDefinition
+		distance float64
+		want     float64
+	}{
+		{name: "below min distance", wall: wall, distance: 0, want: 2},
+		{name: "at min distance", wall: wall, distance: 100, want: 2},
+		{name: "between points", wall: wall, distance: 200, want: 4},
+		{name: "at point", wall: wall, distance: 300, want: 6},
+		{name: "between last points", wall: wall, distance: 450, want: 9},
+		{name: "at max distance", wall: wall, distance: 500, want: 10},
+		{name: "above max distance", wall: wall, distance: 5000, want: 10},
+		{name: "empty curve", wall: &WallDefinition{}, distance: 200, want: movWallSpeedSlow},
+		{name: "default near ball", wall: defaultWallDefinition(), distance: 0, want: movWallSpeedSlow},
+		{name: "default far away", wall: defaultWallDefinition(), distance: ScreenWidth * 2, want: movWallSpeedHight},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.wall.speedAt(tt.distance); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("speedAt(%v) = %v, want %v", tt.distance, got, tt.want)
+			}
+		})
+	}
+}
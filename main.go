@@ -23,23 +23,31 @@ func createDirIfNotExist(dirPath string) error {
 
 func main() {
 
-	bgmPlayer := assets.CreatePlayer()
+	err := createDirIfNotExist(game.GameFilesDir)
+	if err != nil {
+		panic(err)
+	}
 
-	// Set to loop indefinitely
-	bgmPlayer.SetVolume(0.5) // 50% volume
-	bgmPlayer.Play()
+	settings, err := game.LoadSettings()
+	if err != nil {
+		panic(err)
+	}
 
-	err := createDirIfNotExist(game.GameFilesDir)
+	// music and sound effects
+	audio, err := assets.NewAudio()
 	if err != nil {
 		panic(err)
 	}
+	audio.SetMusicVolume(settings.MusicVolume)
+	audio.SetSoundVolume(settings.SFXVolume)
 
-	g, err := game.NewGame()
+	g, err := game.NewGame(settings, audio)
 	if err != nil {
 		panic(err)
 	}
 
-	ebiten.SetWindowSize(game.ScreenWidth, game.ScreenHeight)
+	// window size, fullscreen, vsync and palette
+	settings.Apply()
 	ebiten.SetWindowTitle("Slime")
 
 	if err := ebiten.RunGame(g); err != nil {